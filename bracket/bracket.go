@@ -0,0 +1,295 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bracket checks that the brackets of a text are balanced and properly nested,
+// skipping the quoted strings and the escaped characters.
+package bracket
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chenmingyong0423/algorithms/stack"
+)
+
+// Pair is a pair of brackets, the opening and closing runes may be the same, e.g. |x|.
+type Pair struct {
+	Open  rune
+	Close rune
+}
+
+// Quote is a quoted string in which the brackets are ignored.
+type Quote struct {
+	Open  rune
+	Close rune
+	// Escape makes the rune following it literal inside the quote, zero disables escaping
+	Escape rune
+}
+
+// Position is the position of a rune in the text.
+type Position struct {
+	// Offset is the byte offset
+	Offset int
+	// Line and Column start at 1, the column counts runes
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Delimiter is an opening bracket or quote waiting for its closer.
+type Delimiter struct {
+	Open  rune
+	Close rune
+	Pos   Position
+}
+
+// ErrorKind is the kind of an Error.
+type ErrorKind int
+
+const (
+	// ErrUnexpectedClose is a closing bracket without an opening one
+	ErrUnexpectedClose ErrorKind = iota
+	// ErrMismatch is a closing bracket not matching the last opening one
+	ErrMismatch
+	// ErrUnclosed is an opening bracket not closed at the end of the text
+	ErrUnclosed
+	// ErrUnterminatedQuote is a quote not closed at the end of the text
+	ErrUnterminatedQuote
+)
+
+// Error describes why a text is not balanced.
+type Error struct {
+	Kind ErrorKind
+	// Pos is the position of the unexpected closing bracket, or of the unclosed opening bracket or quote
+	Pos Position
+	// Found is the unexpected closing bracket
+	Found rune
+	// Open is the opening bracket or quote left open, the zero value for ErrUnexpectedClose
+	Open Delimiter
+}
+
+func (e *Error) Error() string {
+	switch e.Kind {
+	case ErrUnexpectedClose:
+		return fmt.Sprintf("bracket: unexpected %q at %s", e.Found, e.Pos)
+	case ErrMismatch:
+		return fmt.Sprintf("bracket: unexpected %q at %s, expected %q to close %q at %s",
+			e.Found, e.Pos, e.Open.Close, e.Open.Open, e.Open.Pos)
+	case ErrUnclosed:
+		return fmt.Sprintf("bracket: unclosed %q at %s, expected %q", e.Open.Open, e.Pos, e.Open.Close)
+	default:
+		return fmt.Sprintf("bracket: unterminated quote %q at %s, expected %q", e.Open.Open, e.Pos, e.Open.Close)
+	}
+}
+
+// Expected returns the closer which was expected, zero for ErrUnexpectedClose.
+func (e *Error) Expected() rune {
+	return e.Open.Close
+}
+
+// Option configures a Matcher.
+type Option func(m *Matcher)
+
+// WithPairs replaces the pairs of brackets, by default (), [] and {}.
+func WithPairs(pairs ...Pair) Option {
+	return func(m *Matcher) {
+		m.pairs = pairs
+	}
+}
+
+// WithQuotes replaces the quotes, by default the double quote and the single quote, both escaped by a backslash.
+func WithQuotes(quotes ...Quote) Option {
+	return func(m *Matcher) {
+		m.quotes = quotes
+	}
+}
+
+// WithEscape sets the rune making the rune following it literal outside of the quotes, zero disables it,
+// which is the default.
+func WithEscape(escape rune) Option {
+	return func(m *Matcher) {
+		m.escape = escape
+	}
+}
+
+// WithStack sets the function returning the stack of the open delimiters, by default a stack.ArrayStack.
+// It is called once per text matched.
+func WithStack(newStack func() stack.Stack[Delimiter]) Option {
+	return func(m *Matcher) {
+		m.newStack = newStack
+	}
+}
+
+// Matcher checks that the brackets of texts are balanced, it is safe for concurrent use.
+type Matcher struct {
+	pairs    []Pair
+	quotes   []Quote
+	escape   rune
+	newStack func() stack.Stack[Delimiter]
+
+	opens  map[rune]Pair
+	closes map[rune]Pair
+	quoted map[rune]Quote
+}
+
+// New returns a new Matcher configured by opts.
+// It panics if a rune is used by two pairs, two quotes, or a pair and a quote.
+func New(opts ...Option) *Matcher {
+	m := &Matcher{
+		pairs:  []Pair{{Open: '(', Close: ')'}, {Open: '[', Close: ']'}, {Open: '{', Close: '}'}},
+		quotes: []Quote{{Open: '"', Close: '"', Escape: '\\'}, {Open: '\'', Close: '\'', Escape: '\\'}},
+		newStack: func() stack.Stack[Delimiter] {
+			return stack.NewArrayStack[Delimiter]()
+		},
+		opens:  make(map[rune]Pair),
+		closes: make(map[rune]Pair),
+		quoted: make(map[rune]Quote),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	used := make(map[rune]bool)
+	use := func(runes ...rune) {
+		for _, r := range runes {
+			if used[r] {
+				panic(fmt.Sprintf("bracket: %q is used twice", r))
+			}
+			used[r] = true
+		}
+	}
+	for _, p := range m.pairs {
+		if p.Open == p.Close {
+			use(p.Open)
+		} else {
+			use(p.Open, p.Close)
+		}
+		m.opens[p.Open] = p
+		m.closes[p.Close] = p
+	}
+	for _, q := range m.quotes {
+		use(q.Open)
+		m.quoted[q.Open] = q
+	}
+	// the quotes may share their closing rune, but it must not open a bracket or another quote
+	for _, q := range m.quotes {
+		if q.Close != q.Open && used[q.Close] {
+			panic(fmt.Sprintf("bracket: %q is used twice", q.Close))
+		}
+	}
+	return m
+}
+
+// Match checks that the brackets of s are balanced.
+// It returns nil if they are and an *Error otherwise.
+func (m *Matcher) Match(s string) error {
+	return m.MatchReader(strings.NewReader(s))
+}
+
+// MatchReader checks that the brackets of the text read from r are balanced, reading r until io.EOF.
+// It returns nil if they are, an *Error if they are not and the error of r if reading fails.
+func (m *Matcher) MatchReader(r io.RuneReader) error {
+	sc := scan{
+		Matcher: m,
+		open:    m.newStack(),
+	}
+	pos := Position{Line: 1, Column: 1}
+	for {
+		c, size, err := r.ReadRune()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := sc.next(c, pos); err != nil {
+			return err
+		}
+		pos.Offset += size
+		if c == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	if sc.inQuote {
+		return &Error{Kind: ErrUnterminatedQuote, Pos: sc.quote.Pos, Open: sc.quote}
+	}
+	// the innermost unclosed bracket is reported, it is the closest to the end
+	if d, ok := sc.open.Pop(); ok {
+		return &Error{Kind: ErrUnclosed, Pos: d.Pos, Open: d}
+	}
+	return nil
+}
+
+// scan is the state of a Matcher while it reads a text
+type scan struct {
+	*Matcher
+	open stack.Stack[Delimiter]
+	// quote is the quote in progress if inQuote
+	quote   Delimiter
+	inQuote bool
+	escaped bool
+}
+
+// next handles the rune c read at pos
+func (sc *scan) next(c rune, pos Position) error {
+	switch {
+	case sc.escaped:
+		sc.escaped = false
+	case sc.inQuote:
+		if escape := sc.quoted[sc.quote.Open].Escape; escape != 0 && c == escape {
+			sc.escaped = true
+		} else if c == sc.quote.Close {
+			sc.inQuote = false
+		}
+	case sc.escape != 0 && c == sc.escape:
+		sc.escaped = true
+	default:
+		if q, ok := sc.quoted[c]; ok {
+			sc.quote = Delimiter{Open: q.Open, Close: q.Close, Pos: pos}
+			sc.inQuote = true
+			return nil
+		}
+		return sc.bracket(c, pos)
+	}
+	return nil
+}
+
+// bracket handles the rune c read at pos outside of the quotes
+func (sc *scan) bracket(c rune, pos Position) error {
+	if p, ok := sc.closes[c]; ok {
+		top, ok := sc.open.Peek()
+		switch {
+		case ok && top.Close == c:
+			sc.open.Pop()
+			return nil
+		// a symmetric pair opens when it does not close
+		case p.Open == c:
+		case !ok:
+			return &Error{Kind: ErrUnexpectedClose, Pos: pos, Found: c}
+		default:
+			return &Error{Kind: ErrMismatch, Pos: pos, Found: c, Open: top}
+		}
+	}
+	if p, ok := sc.opens[c]; ok {
+		sc.open.Push(Delimiter{Open: p.Open, Close: p.Close, Pos: pos})
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bracket
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/stack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	testCases := []struct {
+		name    string
+		matcher *Matcher
+		input   string
+		wantErr *Error
+		wantMsg string
+	}{
+		{
+			name:    "balanced",
+			matcher: New(),
+			input:   "f(a[1], {b: [2, (3)]})",
+		},
+		{
+			name:    "empty",
+			matcher: New(),
+			input:   "",
+		},
+		{
+			name:    "brackets in quotes are ignored",
+			matcher: New(),
+			input:   `say("(", ')', "\")[")`,
+		},
+		{
+			name:    "unexpected close",
+			matcher: New(),
+			input:   "a)\n",
+			wantErr: &Error{Kind: ErrUnexpectedClose, Pos: Position{Offset: 1, Line: 1, Column: 2}, Found: ')'},
+			wantMsg: "bracket: unexpected ')' at 1:2",
+		},
+		{
+			name:    "mismatch",
+			matcher: New(),
+			input:   "{\n  (é]\n}",
+			wantErr: &Error{
+				Kind:  ErrMismatch,
+				Pos:   Position{Offset: 7, Line: 2, Column: 5},
+				Found: ']',
+				Open:  Delimiter{Open: '(', Close: ')', Pos: Position{Offset: 4, Line: 2, Column: 3}},
+			},
+			wantMsg: "bracket: unexpected ']' at 2:5, expected ')' to close '(' at 2:3",
+		},
+		{
+			name:    "unclosed",
+			matcher: New(),
+			input:   "[(a)\n",
+			wantErr: &Error{
+				Kind: ErrUnclosed,
+				Pos:  Position{Offset: 0, Line: 1, Column: 1},
+				Open: Delimiter{Open: '[', Close: ']', Pos: Position{Offset: 0, Line: 1, Column: 1}},
+			},
+			wantMsg: "bracket: unclosed '[' at 1:1, expected ']'",
+		},
+		{
+			name:    "unterminated quote",
+			matcher: New(),
+			input:   `("a\")`,
+			wantErr: &Error{
+				Kind: ErrUnterminatedQuote,
+				Pos:  Position{Offset: 1, Line: 1, Column: 2},
+				Open: Delimiter{Open: '"', Close: '"', Pos: Position{Offset: 1, Line: 1, Column: 2}},
+			},
+			wantMsg: `bracket: unterminated quote '"' at 1:2, expected '"'`,
+		},
+		{
+			name: "custom pairs and quotes",
+			matcher: New(
+				WithPairs(Pair{Open: '<', Close: '>'}, Pair{Open: '|', Close: '|'}),
+				WithQuotes(Quote{Open: '«', Close: '»'}),
+			),
+			input: `<|a| «>\» <|<b>|>>`,
+		},
+		{
+			name:    "symmetric pair mismatch",
+			matcher: New(WithPairs(Pair{Open: '(', Close: ')'}, Pair{Open: '|', Close: '|'})),
+			input:   "|(|)",
+			// the second | opens since the innermost bracket is (
+			wantErr: &Error{
+				Kind:  ErrMismatch,
+				Pos:   Position{Offset: 3, Line: 1, Column: 4},
+				Found: ')',
+				Open:  Delimiter{Open: '|', Close: '|', Pos: Position{Offset: 2, Line: 1, Column: 3}},
+			},
+		},
+		{
+			name:    "escape outside of quotes",
+			matcher: New(WithEscape('\\')),
+			input:   `\(a\]`,
+		},
+		{
+			name:    "no escape outside of quotes by default",
+			matcher: New(),
+			input:   `\(`,
+			wantErr: &Error{
+				Kind: ErrUnclosed,
+				Pos:  Position{Offset: 1, Line: 1, Column: 2},
+				Open: Delimiter{Open: '(', Close: ')', Pos: Position{Offset: 1, Line: 1, Column: 2}},
+			},
+		},
+		{
+			name: "linked list stack",
+			matcher: New(WithStack(func() stack.Stack[Delimiter] {
+				return stack.NewLinkedListStack[Delimiter]()
+			})),
+			input: "([)]",
+			wantErr: &Error{
+				Kind:  ErrMismatch,
+				Pos:   Position{Offset: 2, Line: 1, Column: 3},
+				Found: ')',
+				Open:  Delimiter{Open: '[', Close: ']', Pos: Position{Offset: 1, Line: 1, Column: 2}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.matcher.Match(tc.input)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var got *Error
+			assert.True(t, errors.As(err, &got))
+			assert.Equal(t, tc.wantErr, got)
+			if tc.wantMsg != "" {
+				assert.EqualError(t, err, tc.wantMsg)
+			}
+		})
+	}
+}
+
+func TestMatcher_MatchReader(t *testing.T) {
+	m := New(WithStack(func() stack.Stack[Delimiter] {
+		return stack.NewLinkedListStackWithList[Delimiter](linkedlist.NewDoublyLinkedList[Delimiter]())
+	}))
+	input := strings.Repeat("{[(", 10000) + strings.Repeat(")]}", 10000)
+	assert.NoError(t, m.MatchReader(bufio.NewReader(strings.NewReader(input))))
+
+	err := m.MatchReader(bufio.NewReader(strings.NewReader(input + "}")))
+	var got *Error
+	assert.True(t, errors.As(err, &got))
+	assert.Equal(t, '}', got.Found)
+	assert.Equal(t, len(input), got.Pos.Offset)
+	assert.Equal(t, rune(0), got.Expected())
+
+	failure := errors.New("failure")
+	err = m.MatchReader(bufio.NewReader(io.MultiReader(strings.NewReader("(("), &failingReader{err: failure})))
+	assert.Equal(t, failure, err)
+}
+
+func TestNew_Conflict(t *testing.T) {
+	assert.Panics(t, func() {
+		New(WithPairs(Pair{Open: '(', Close: ')'}, Pair{Open: ')', Close: '('}))
+	})
+	assert.Panics(t, func() {
+		New(WithQuotes(Quote{Open: '(', Close: ')'}))
+	})
+	assert.Panics(t, func() {
+		New(WithQuotes(Quote{Open: '<', Close: ')'}))
+	})
+	assert.Panics(t, func() {
+		New(WithQuotes(Quote{Open: '<', Close: '"'}, Quote{Open: '"', Close: '"'}))
+	})
+	assert.NotPanics(t, func() {
+		New(WithQuotes(Quote{Open: '<', Close: '>'}, Quote{Open: '«', Close: '>'}))
+	})
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
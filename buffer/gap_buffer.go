@@ -0,0 +1,170 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var _ linkedlist.ReadOnlyList[any] = (*GapBuffer[any])(nil)
+
+// defaultGapSize is the size of the gap allocated when the gap is full
+const defaultGapSize = 64
+
+// GapBuffer is a sequence keeping a gap at the cursor, so that inserting and deleting
+// at the cursor is amortized O(1) and moving the cursor is O(distance).
+type GapBuffer[T any] struct {
+	data []T
+	// the gap is data[gapStart:gapEnd], the cursor is at gapStart
+	gapStart int
+	gapEnd   int
+}
+
+// NewGapBuffer returns a new gap buffer containing the elements, with the cursor at the end.
+func NewGapBuffer[T any](elements ...T) *GapBuffer[T] {
+	b := NewGapBufferWithOptions[T](option.WithCapacity[T](len(elements) + defaultGapSize))
+	b.Insert(elements...)
+	return b
+}
+
+// NewGapBufferWithOptions returns a new empty gap buffer configured by opts.
+// Only option.WithCapacity is honoured.
+func NewGapBufferWithOptions[T any](opts ...option.Option[T]) *GapBuffer[T] {
+	o := option.Apply(opts...)
+	capacity := max(o.Capacity, defaultGapSize)
+	return &GapBuffer[T]{
+		data:   make([]T, capacity),
+		gapEnd: capacity,
+	}
+}
+
+// Cursor returns the position of the cursor, in [0, Size()].
+func (b *GapBuffer[T]) Cursor() int {
+	return b.gapStart
+}
+
+// MoveCursor moves the cursor before the element at the position.
+// If the position is not in [0, Size()], b return false
+func (b *GapBuffer[T]) MoveCursor(pos int) bool {
+	if pos < 0 || pos > b.Size() {
+		return false
+	}
+	if pos < b.gapStart {
+		// move the elements in [pos, gapStart) after the gap
+		n := b.gapStart - pos
+		copy(b.data[b.gapEnd-n:b.gapEnd], b.data[pos:b.gapStart])
+		b.clear(pos, min(b.gapStart, b.gapEnd-n))
+		b.gapStart, b.gapEnd = pos, b.gapEnd-n
+	} else if pos > b.gapStart {
+		// move the elements in [gapEnd, gapEnd+n) before the gap
+		n := pos - b.gapStart
+		copy(b.data[b.gapStart:pos], b.data[b.gapEnd:b.gapEnd+n])
+		b.clear(max(pos, b.gapEnd), b.gapEnd+n)
+		b.gapStart, b.gapEnd = pos, b.gapEnd+n
+	}
+	return true
+}
+
+// Insert inserts the elements at the cursor and moves the cursor after them.
+func (b *GapBuffer[T]) Insert(elements ...T) {
+	if len(elements) > b.gapEnd-b.gapStart {
+		b.grow(len(elements))
+	}
+	copy(b.data[b.gapStart:], elements)
+	b.gapStart += len(elements)
+}
+
+// Delete deletes at most n elements before the cursor, like a backspace, and returns them.
+func (b *GapBuffer[T]) Delete(n int) []T {
+	n = min(max(n, 0), b.gapStart)
+	deleted := make([]T, n)
+	copy(deleted, b.data[b.gapStart-n:b.gapStart])
+	b.clear(b.gapStart-n, b.gapStart)
+	b.gapStart -= n
+	return deleted
+}
+
+// DeleteForward deletes at most n elements after the cursor and returns them.
+func (b *GapBuffer[T]) DeleteForward(n int) []T {
+	n = min(max(n, 0), len(b.data)-b.gapEnd)
+	deleted := make([]T, n)
+	copy(deleted, b.data[b.gapEnd:b.gapEnd+n])
+	b.clear(b.gapEnd, b.gapEnd+n)
+	b.gapEnd += n
+	return deleted
+}
+
+// Get returns the element at the specified position.
+// If the index is invalid, b return false
+func (g *GapBuffer[T]) Get(index int) (t T, b bool) {
+	if index < 0 || index >= g.Size() {
+		return
+	}
+	return g.data[g.physical(index)], true
+}
+
+// Set sets the element at the specified position.
+// If the index is invalid, b return false
+func (b *GapBuffer[T]) Set(index int, e T) bool {
+	if index < 0 || index >= b.Size() {
+		return false
+	}
+	b.data[b.physical(index)] = e
+	return true
+}
+
+// IsEmpty checks whether the buffer is empty
+func (b *GapBuffer[T]) IsEmpty() bool {
+	return b.Size() == 0
+}
+
+// Size returns the number of elements of the buffer
+func (b *GapBuffer[T]) Size() int {
+	return len(b.data) - (b.gapEnd - b.gapStart)
+}
+
+// Values returns a slice containing all the elements of the buffer.
+func (b *GapBuffer[T]) Values() []T {
+	elements := make([]T, 0, b.Size())
+	elements = append(elements, b.data[:b.gapStart]...)
+	return append(elements, b.data[b.gapEnd:]...)
+}
+
+// physical returns the position in data of the element at the index
+func (b *GapBuffer[T]) physical(index int) int {
+	if index < b.gapStart {
+		return index
+	}
+	return index + b.gapEnd - b.gapStart
+}
+
+// grow makes room for at least n elements in the gap
+func (b *GapBuffer[T]) grow(n int) {
+	capacity := max(2*len(b.data), len(b.data)+n+defaultGapSize)
+	data := make([]T, capacity)
+	copy(data, b.data[:b.gapStart])
+	after := len(b.data) - b.gapEnd
+	copy(data[capacity-after:], b.data[b.gapEnd:])
+	b.data, b.gapEnd = data, capacity-after
+}
+
+// clear zeroes data[from:to], so that the elements in the gap can be garbage collected
+func (b *GapBuffer[T]) clear(from, to int) {
+	var zero T
+	for i := from; i < to; i++ {
+		b.data[i] = zero
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGapBuffer(t *testing.T) {
+	b := NewGapBuffer[rune]([]rune("helo")...)
+	assert.Equal(t, 4, b.Cursor())
+	assert.True(t, b.MoveCursor(3))
+	b.Insert('l')
+	assert.Equal(t, "hello", string(b.Values()))
+	assert.Equal(t, 4, b.Cursor())
+
+	assert.False(t, b.MoveCursor(6))
+	assert.True(t, b.MoveCursor(5))
+	b.Insert([]rune(" world")...)
+	assert.True(t, b.MoveCursor(0))
+	assert.Equal(t, []rune("hello"), b.DeleteForward(5))
+	assert.Empty(t, b.Delete(1))
+	b.Insert('H', 'i')
+	assert.Equal(t, "Hi world", string(b.Values()))
+	assert.Equal(t, []rune("Hi"), b.Delete(5))
+
+	c, ok := b.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 'w', c)
+	assert.True(t, b.Set(1, 'W'))
+	_, ok = b.Get(b.Size())
+	assert.False(t, ok)
+	assert.Equal(t, " World", string(b.Values()))
+	assert.False(t, b.IsEmpty())
+}
+
+func TestGapBuffer_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	b := NewGapBufferWithOptions[int](option.WithCapacity[int](4))
+	var want []int
+	for i := 0; i < 2000; i++ {
+		switch r.Intn(4) {
+		case 0:
+			pos := r.Intn(len(want) + 1)
+			assert.True(t, b.MoveCursor(pos))
+		case 1:
+			elements := make([]int, r.Intn(100))
+			for j := range elements {
+				elements[j] = r.Int()
+			}
+			pos := b.Cursor()
+			b.Insert(elements...)
+			want = append(want[:pos:pos], append(elements, want[pos:]...)...)
+		case 2:
+			n, pos := r.Intn(20), b.Cursor()
+			deleted := b.Delete(n)
+			assert.Equal(t, want[pos-len(deleted):pos], deleted)
+			want = append(want[:pos-len(deleted):pos-len(deleted)], want[pos:]...)
+		default:
+			n, pos := r.Intn(20), b.Cursor()
+			deleted := b.DeleteForward(n)
+			assert.Equal(t, want[pos:pos+len(deleted)], deleted)
+			want = append(want[:pos:pos], want[pos+len(deleted):]...)
+		}
+		assert.Equal(t, len(want), b.Size())
+	}
+	assert.Equal(t, want, b.Values())
+	for i, e := range want {
+		got, _ := b.Get(i)
+		assert.Equal(t, e, got)
+	}
+	// the gap holds no element
+	for i := b.gapStart; i < b.gapEnd; i++ {
+		assert.Zero(t, b.data[i])
+	}
+}
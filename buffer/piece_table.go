@@ -0,0 +1,232 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+
+var _ linkedlist.ReadOnlyList[any] = (*PieceTable[any])(nil)
+
+// piece is a span of the original or of the add buffer of a piece table
+type piece struct {
+	added  bool
+	start  int
+	length int
+}
+
+// change replaces the pieces removed at the position by the inserted ones, it is undone by swapping them
+type change struct {
+	at       int
+	removed  []piece
+	inserted []piece
+}
+
+// PieceTable is a sequence made of pieces of a read-only original buffer and an append-only add buffer.
+// Editing only changes the list of pieces, which makes every edit cheap to undo and redo.
+type PieceTable[T any] struct {
+	original []T
+	add      []T
+	pieces   []piece
+	size     int
+
+	undo []change
+	redo []change
+	// coalesce tells whether the last change is an insertion that the next adjacent insertion can extend
+	coalesce bool
+}
+
+// NewPieceTable returns a new piece table whose original buffer is a copy of the elements.
+func NewPieceTable[T any](elements ...T) *PieceTable[T] {
+	t := &PieceTable[T]{
+		original: append([]T(nil), elements...),
+		size:     len(elements),
+	}
+	if len(elements) > 0 {
+		t.pieces = []piece{{start: 0, length: len(elements)}}
+	}
+	return t
+}
+
+// Insert inserts the elements before the index.
+// Consecutive insertions at the end of the previous one, like typing, extend the same piece and are undone together.
+// If the index is not in [0, Size()], b return false
+func (t *PieceTable[T]) Insert(index int, elements ...T) bool {
+	if index < 0 || index > t.size {
+		return false
+	}
+	if len(elements) == 0 {
+		return true
+	}
+	start := len(t.add)
+	t.add = append(t.add, elements...)
+	i, offset := t.find(index)
+	t.redo = nil
+
+	if t.coalesce && offset == 0 && i > 0 {
+		prev := &t.pieces[i-1]
+		if prev.added && prev.start+prev.length == start {
+			last := &t.undo[len(t.undo)-1]
+			for j := range last.inserted {
+				if last.inserted[j] == *prev {
+					last.inserted[j].length += len(elements)
+					prev.length += len(elements)
+					t.size += len(elements)
+					return true
+				}
+			}
+		}
+	}
+
+	inserted := piece{added: true, start: start, length: len(elements)}
+	if offset == 0 {
+		t.apply(change{at: i, inserted: []piece{inserted}})
+	} else {
+		p := t.pieces[i]
+		t.apply(change{
+			at:      i,
+			removed: []piece{p},
+			inserted: []piece{
+				{added: p.added, start: p.start, length: offset},
+				inserted,
+				{added: p.added, start: p.start + offset, length: p.length - offset},
+			},
+		})
+	}
+	t.coalesce = true
+	return true
+}
+
+// Delete deletes the elements in [from, to).
+// If the range is not within [0, Size()], b return false
+func (t *PieceTable[T]) Delete(from, to int) bool {
+	if from < 0 || to > t.size || from > to {
+		return false
+	}
+	if from == to {
+		return true
+	}
+	first, firstOffset := t.find(from)
+	last, lastOffset := t.find(to)
+	// the range ends inside the last piece, or right before it
+	if lastOffset == 0 {
+		last--
+		lastOffset = t.pieces[last].length
+	}
+	c := change{at: first, removed: append([]piece(nil), t.pieces[first:last+1]...)}
+	if p := t.pieces[first]; firstOffset > 0 {
+		c.inserted = append(c.inserted, piece{added: p.added, start: p.start, length: firstOffset})
+	}
+	if p := t.pieces[last]; lastOffset < p.length {
+		c.inserted = append(c.inserted, piece{added: p.added, start: p.start + lastOffset, length: p.length - lastOffset})
+	}
+	t.redo = nil
+	t.apply(c)
+	t.coalesce = false
+	return true
+}
+
+// Undo undoes the last change.
+// If there is nothing to undo, b return false
+func (t *PieceTable[T]) Undo() bool {
+	if len(t.undo) == 0 {
+		return false
+	}
+	c := t.undo[len(t.undo)-1]
+	t.undo = t.undo[:len(t.undo)-1]
+	t.replace(c.at, len(c.inserted), c.removed)
+	t.redo = append(t.redo, c)
+	t.coalesce = false
+	return true
+}
+
+// Redo redoes the last undone change.
+// If there is nothing to redo, b return false
+func (t *PieceTable[T]) Redo() bool {
+	if len(t.redo) == 0 {
+		return false
+	}
+	c := t.redo[len(t.redo)-1]
+	t.redo = t.redo[:len(t.redo)-1]
+	t.replace(c.at, len(c.removed), c.inserted)
+	t.undo = append(t.undo, c)
+	t.coalesce = false
+	return true
+}
+
+// Get returns the element at the specified position.
+// If the index is invalid, b return false
+func (t *PieceTable[T]) Get(index int) (e T, b bool) {
+	if index < 0 || index >= t.size {
+		return
+	}
+	i, offset := t.find(index)
+	p := t.pieces[i]
+	return t.buffer(p)[p.start+offset], true
+}
+
+// IsEmpty checks whether the table is empty
+func (t *PieceTable[T]) IsEmpty() bool {
+	return t.size == 0
+}
+
+// Size returns the number of elements of the table
+func (t *PieceTable[T]) Size() int {
+	return t.size
+}
+
+// Values returns a slice containing all the elements of the table.
+func (t *PieceTable[T]) Values() []T {
+	elements := make([]T, 0, t.size)
+	for _, p := range t.pieces {
+		elements = append(elements, t.buffer(p)[p.start:p.start+p.length]...)
+	}
+	return elements
+}
+
+// find returns the piece containing the index and the offset of the index in the piece.
+// For the index Size(), it returns the number of pieces and zero.
+func (t *PieceTable[T]) find(index int) (int, int) {
+	for i, p := range t.pieces {
+		if index < p.length {
+			return i, index
+		}
+		index -= p.length
+	}
+	return len(t.pieces), 0
+}
+
+func (t *PieceTable[T]) buffer(p piece) []T {
+	if p.added {
+		return t.add
+	}
+	return t.original
+}
+
+// apply applies the change and records it for undo
+func (t *PieceTable[T]) apply(c change) {
+	t.replace(c.at, len(c.removed), c.inserted)
+	t.undo = append(t.undo, c)
+}
+
+// replace replaces the n pieces at the position by the pieces
+func (t *PieceTable[T]) replace(at, n int, pieces []piece) {
+	for _, p := range t.pieces[at : at+n] {
+		t.size -= p.length
+	}
+	for _, p := range pieces {
+		t.size += p.length
+	}
+	tail := append([]piece(nil), t.pieces[at+n:]...)
+	t.pieces = append(append(t.pieces[:at], pieces...), tail...)
+}
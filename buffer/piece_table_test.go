@@ -0,0 +1,103 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPieceTable(t *testing.T) {
+	p := NewPieceTable[rune]([]rune("hello world")...)
+	assert.True(t, p.Insert(5, ','))
+	assert.True(t, p.Delete(0, 1))
+	assert.True(t, p.Insert(0, 'H'))
+	assert.Equal(t, "Hello, world", string(p.Values()))
+	assert.False(t, p.Insert(13, '!'))
+	assert.False(t, p.Delete(5, 13))
+
+	// typing is coalesced into a single change
+	assert.True(t, p.Insert(12, '!'))
+	assert.True(t, p.Insert(13, '!'))
+	assert.Equal(t, "Hello, world!!", string(p.Values()))
+	assert.True(t, p.Undo())
+	assert.Equal(t, "Hello, world", string(p.Values()))
+	assert.True(t, p.Undo())
+	assert.True(t, p.Undo())
+	assert.True(t, p.Undo())
+	assert.False(t, p.Undo())
+	assert.Equal(t, "hello world", string(p.Values()))
+
+	assert.True(t, p.Redo())
+	assert.True(t, p.Redo())
+	assert.Equal(t, "ello, world", string(p.Values()))
+	assert.True(t, p.Insert(0, 'E'))
+	assert.False(t, p.Redo())
+	assert.Equal(t, "Eello, world", string(p.Values()))
+
+	c, ok := p.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 'e', c)
+	_, ok = p.Get(p.Size())
+	assert.False(t, ok)
+}
+
+func TestPieceTable_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	original := make([]int, 100)
+	for i := range original {
+		original[i] = r.Int()
+	}
+	p := NewPieceTable[int](original...)
+	history := [][]int{p.Values()}
+	for i := 0; i < 1000; i++ {
+		want := p.Values()
+		switch r.Intn(3) {
+		case 0:
+			elements := make([]int, 1+r.Intn(5))
+			for j := range elements {
+				elements[j] = r.Int()
+			}
+			idx := r.Intn(len(want) + 1)
+			assert.True(t, p.Insert(idx, elements...))
+			want = append(want[:idx:idx], append(elements, want[idx:]...)...)
+		case 1:
+			from := r.Intn(len(want) + 1)
+			to := from + r.Intn(len(want)-from+1)
+			assert.True(t, p.Delete(from, to))
+			want = append(want[:from:from], want[to:]...)
+		default:
+			idx := r.Intn(len(want) + 1)
+			if idx < len(want) {
+				got, _ := p.Get(idx)
+				assert.Equal(t, want[idx], got)
+			}
+			continue
+		}
+		assert.Equal(t, want, p.Values())
+		assert.Equal(t, len(want), p.Size())
+		history = append(history, want)
+	}
+	// undo everything, coalesced insertions skip intermediate states
+	for p.Undo() {
+		assert.Contains(t, history, p.Values())
+	}
+	assert.Equal(t, original, p.Values())
+	for p.Redo() {
+	}
+	assert.Equal(t, history[len(history)-1], p.Values())
+}
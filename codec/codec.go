@@ -0,0 +1,56 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codec encodes the elements of the collections persisted to disk and decodes them back.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec encodes the elements written to disk and decodes them back.
+type Codec[T any] interface {
+	Encode(e T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// GobCodec encodes the elements with encoding/gob.
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) Encode(e T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec[T]) Decode(data []byte) (e T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&e)
+	return e, err
+}
+
+// JSONCodec encodes the elements with encoding/json.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(e T) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (e T, err error) {
+	err = json.Unmarshal(data, &e)
+	return e, err
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type point struct {
+	X, Y int
+	Name string
+}
+
+func TestCodecs(t *testing.T) {
+	codecs := map[string]Codec[point]{
+		"gob":  GobCodec[point]{},
+		"json": JSONCodec[point]{},
+	}
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			p := point{X: 1, Y: -2, Name: "p"}
+			data, err := codec.Encode(p)
+			assert.NoError(t, err)
+			got, err := codec.Decode(data)
+			assert.NoError(t, err)
+			assert.Equal(t, p, got)
+			_, err = codec.Decode([]byte{0xff})
+			assert.Error(t, err)
+		})
+	}
+}
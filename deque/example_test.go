@@ -0,0 +1,98 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deque_test
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/chenmingyong0423/algorithms/deque"
+)
+
+// task is a unit of work which may spawn more tasks
+type task func(spawn func(task))
+
+// scheduler runs tasks on a pool of workers, each owning a deque:
+// a worker runs the tasks it spawns itself first, and steals from the others when it runs out of work.
+type scheduler struct {
+	deques  []*deque.WorkStealingDeque[task]
+	pending atomic.Int64
+}
+
+func newScheduler(workers int) *scheduler {
+	s := &scheduler{}
+	for i := 0; i < workers; i++ {
+		s.deques = append(s.deques, deque.NewWorkStealingDeque[task](0))
+	}
+	return s
+}
+
+// run runs the task and all the tasks it spawns, and returns when they are all done
+func (s *scheduler) run(root task) {
+	s.pending.Add(1)
+	s.deques[0].Push(root)
+	var wg sync.WaitGroup
+	for i := range s.deques {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.work(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// work is the loop of the worker i, it owns s.deques[i]
+func (s *scheduler) work(i int) {
+	own := s.deques[i]
+	spawn := func(t task) {
+		s.pending.Add(1)
+		own.Push(t)
+	}
+	for s.pending.Load() > 0 {
+		t, ok := own.Pop()
+		if !ok {
+			t, ok = s.deques[rand.Intn(len(s.deques))].Steal()
+		}
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		t(spawn)
+		s.pending.Add(-1)
+	}
+}
+
+// Example runs a scheduler computing a Fibonacci number by splitting it into tasks.
+func Example() {
+	var result atomic.Int64
+	var fib func(n int) task
+	fib = func(n int) task {
+		return func(spawn func(task)) {
+			if n < 2 {
+				result.Add(int64(n))
+				return
+			}
+			spawn(fib(n - 1))
+			spawn(fib(n - 2))
+		}
+	}
+	newScheduler(4).run(fib(20))
+	fmt.Println("fib(20) =", result.Load())
+	// Output: fib(20) = 6765
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deque implements the Chase-Lev work-stealing deque used by task schedulers.
+package deque
+
+import "sync/atomic"
+
+// defaultCapacity is the initial capacity of a deque if none is given
+const defaultCapacity = 32
+
+// ring is a circular array of a power of two size, indexed by the ever increasing positions of the deque
+type ring[T any] struct {
+	slots []atomic.Pointer[T]
+	mask  int64
+}
+
+func newRing[T any](capacity int64) *ring[T] {
+	return &ring[T]{
+		slots: make([]atomic.Pointer[T], capacity),
+		mask:  capacity - 1,
+	}
+}
+
+func (r *ring[T]) get(i int64) *T {
+	return r.slots[i&r.mask].Load()
+}
+
+func (r *ring[T]) put(i int64, e *T) {
+	r.slots[i&r.mask].Store(e)
+}
+
+// grow returns a ring twice as large holding the elements [top, bottom)
+func (r *ring[T]) grow(top, bottom int64) *ring[T] {
+	bigger := newRing[T](2 * int64(len(r.slots)))
+	for i := top; i < bottom; i++ {
+		bigger.put(i, r.get(i))
+	}
+	return bigger
+}
+
+// WorkStealingDeque is a lock-free Chase-Lev work-stealing deque.
+// A single goroutine, the owner, pushes and pops elements at the bottom like a stack,
+// while any number of goroutines, the thieves, steal elements from the top.
+// The owner works on the most recent elements while the thieves take the oldest ones,
+// so they rarely contend. The circular array grows as needed and is never copied by the thieves.
+type WorkStealingDeque[T any] struct {
+	// top is the position of the next element to steal, only ever increased
+	top atomic.Int64
+	// bottom is the position of the next element to push, only changed by the owner
+	bottom atomic.Int64
+	array  atomic.Pointer[ring[T]]
+}
+
+// NewWorkStealingDeque returns a new deque with room for capacity elements before growing,
+// rounded up to a power of two. If capacity is not positive, 32 is used.
+func NewWorkStealingDeque[T any](capacity int) *WorkStealingDeque[T] {
+	if capacity <= 0 {
+		capacity = defaultCapacity
+	}
+	size := int64(1)
+	for size < int64(capacity) {
+		size <<= 1
+	}
+	d := &WorkStealingDeque[T]{}
+	d.array.Store(newRing[T](size))
+	return d
+}
+
+// Push adds an element to the bottom of the deque, it must only be called by the owner
+func (d *WorkStealingDeque[T]) Push(e T) {
+	b := d.bottom.Load()
+	t := d.top.Load()
+	a := d.array.Load()
+	if b-t >= int64(len(a.slots)) {
+		a = a.grow(t, b)
+		d.array.Store(a)
+	}
+	a.put(b, &e)
+	d.bottom.Store(b + 1)
+}
+
+// Pop removes the element at the bottom of the deque and returns that element,
+// it must only be called by the owner
+// If the deque is empty, b return false
+func (d *WorkStealingDeque[T]) Pop() (t T, b bool) {
+	bottom := d.bottom.Load() - 1
+	a := d.array.Load()
+	// taking the bottom first keeps the thieves off the element, unless it is the last one
+	d.bottom.Store(bottom)
+	top := d.top.Load()
+	if top > bottom {
+		d.bottom.Store(bottom + 1)
+		return
+	}
+	e := a.get(bottom)
+	if top == bottom {
+		// the last element goes to whoever moves the top first, the owner or a thief
+		won := d.top.CompareAndSwap(top, top+1)
+		d.bottom.Store(bottom + 1)
+		if !won {
+			return
+		}
+	}
+	// the slot is cleared so that the element can be garbage collected, a thief which read it fails its CAS
+	a.put(bottom, nil)
+	return *e, true
+}
+
+// Steal removes the element at the top of the deque and returns that element, it may be called by any goroutine.
+// It retries when another thief or the owner takes the element first.
+// If the deque is empty, b return false
+func (d *WorkStealingDeque[T]) Steal() (t T, b bool) {
+	for {
+		top := d.top.Load()
+		bottom := d.bottom.Load()
+		if top >= bottom {
+			return
+		}
+		e := d.array.Load().get(top)
+		if d.top.CompareAndSwap(top, top+1) {
+			return *e, true
+		}
+	}
+}
+
+// Size returns the number of elements of the deque, it may be off while operations are in progress
+func (d *WorkStealingDeque[T]) Size() int {
+	return int(max(d.bottom.Load()-d.top.Load(), 0))
+}
+
+// IsEmpty checks whether the deque is empty, it may be off while operations are in progress
+func (d *WorkStealingDeque[T]) IsEmpty() bool {
+	return d.Size() == 0
+}
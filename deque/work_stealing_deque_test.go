@@ -0,0 +1,146 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deque
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkStealingDeque(t *testing.T) {
+	d := NewWorkStealingDeque[int](0)
+	assert.Len(t, d.array.Load().slots, defaultCapacity)
+	_, ok := d.Pop()
+	assert.False(t, ok)
+	_, ok = d.Steal()
+	assert.False(t, ok)
+	assert.True(t, d.IsEmpty())
+
+	for i := 0; i < 5; i++ {
+		d.Push(i)
+	}
+	assert.Equal(t, 5, d.Size())
+	// the owner pops the newest elements and the thieves steal the oldest ones
+	v, ok := d.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	v, ok = d.Steal()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+	v, ok = d.Steal()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, ok = d.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	v, ok = d.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	_, ok = d.Pop()
+	assert.False(t, ok)
+	assert.True(t, d.IsEmpty())
+}
+
+func TestWorkStealingDeque_Grow(t *testing.T) {
+	d := NewWorkStealingDeque[int](3)
+	assert.Len(t, d.array.Load().slots, 4)
+	// moves the positions so that the elements wrap around the ring
+	for i := 0; i < 3; i++ {
+		d.Push(-1)
+		d.Steal()
+	}
+	for i := 0; i < 10; i++ {
+		d.Push(i)
+	}
+	assert.Len(t, d.array.Load().slots, 16)
+	for i := 0; i < 5; i++ {
+		v, ok := d.Steal()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	for i := 9; i >= 5; i-- {
+		v, ok := d.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestWorkStealingDeque_ClearPoppedSlots(t *testing.T) {
+	d := NewWorkStealingDeque[int](4)
+	d.Push(1)
+	d.Push(2)
+	d.Pop()
+	d.Pop()
+	a := d.array.Load()
+	for i := range a.slots {
+		assert.Nil(t, a.slots[i].Load(), "slot %d", i)
+	}
+}
+
+func TestWorkStealingDeque_Concurrent(t *testing.T) {
+	const n, thieves = 20000, 4
+	d := NewWorkStealingDeque[int](2)
+	taken := make([][]int, thieves+1)
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 1; i <= thieves; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				if v, ok := d.Steal(); ok {
+					taken[i] = append(taken[i], v)
+					continue
+				}
+				select {
+				case <-done:
+					return
+				default:
+					runtime.Gosched()
+				}
+			}
+		}(i)
+	}
+	// the owner pops one element every three pushes, racing the thieves for the last ones
+	for i := 0; i < n; i++ {
+		d.Push(i)
+		if i%3 == 0 {
+			if v, ok := d.Pop(); ok {
+				taken[0] = append(taken[0], v)
+			}
+		}
+	}
+	for v, ok := d.Pop(); ok; v, ok = d.Pop() {
+		taken[0] = append(taken[0], v)
+	}
+	close(done)
+	wg.Wait()
+
+	// every element is taken exactly once
+	seen := make([]bool, n)
+	count := 0
+	for _, values := range taken {
+		for _, v := range values {
+			assert.False(t, seen[v], v)
+			seen[v] = true
+			count++
+		}
+	}
+	assert.Equal(t, n, count)
+	assert.True(t, d.IsEmpty())
+}
@@ -0,0 +1,360 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package durable
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chenmingyong0423/algorithms/codec"
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+)
+
+var _ linkedlist.LinkedList[any] = (*List[any])(nil)
+
+// List is a LinkedList appending every mutation to a write-ahead log before applying it to the wrapped list,
+// and replaying the log when it is opened again.
+// The log is compacted into a snapshot of the elements every so often, see WithCompactEvery.
+//
+// A mutation that cannot be logged is not applied: the method reports a failure, e.g. Set returns false,
+// and Err returns the error. The list then rejects all the mutations, since the end of the log is unknown,
+// and has to be opened again.
+//
+// List is not safe for concurrent use, wrap it in a linkedlist.ConcurrentLinkedList for that.
+type List[T any] struct {
+	list    linkedlist.LinkedList[T]
+	dir     string
+	codec   codec.Codec[T]
+	encoder encoder[T]
+	config  config
+
+	log        *os.File
+	generation uint64
+	// records is the number of records in the log since the last compaction
+	records  int
+	frame    []byte
+	lastSync time.Time
+	dirty    bool
+
+	err    error
+	closed bool
+}
+
+// Open opens the list persisted in dir, which is created if needed,
+// and replays its snapshot and its log into list, which must be empty.
+// The list must be configured the same way every time, e.g. with the same max size and overflow policy,
+// for the replay to rebuild the same elements. Its hooks are called during the replay.
+func Open[T any](dir string, list linkedlist.LinkedList[T], codec codec.Codec[T], opts ...Option) (*List[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	l := &List[T]{
+		list:    list,
+		dir:     dir,
+		codec:   codec,
+		encoder: encoder[T]{codec: codec},
+		config:  newConfig(opts),
+	}
+	if err := l.recover(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// recover replays the snapshot and the log, then opens the log for appending
+func (l *List[T]) recover() error {
+	generation, elements, err := readSnapshot(filepath.Join(l.dir, snapshotName), l.codec)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	l.generation = generation
+	if len(elements) > 0 {
+		l.list.Add(elements...)
+	}
+
+	logPath := filepath.Join(l.dir, logName)
+	logGeneration, records, valid, err := readLog(logPath, l.codec)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) || err == nil && logGeneration < generation:
+		// there is no log yet, or the crash happened during a compaction, after the snapshot was written
+		if err = writeEmptyLog(logPath, generation); err != nil {
+			return err
+		}
+		valid = headerSize
+	case err != nil:
+		return err
+	case logGeneration > generation:
+		return fmt.Errorf("%w: %s: generation %d is newer than the snapshot %d", ErrCorrupt, logPath, logGeneration, generation)
+	default:
+		for _, r := range records {
+			l.apply(r)
+		}
+		l.records = len(records)
+	}
+
+	if l.log, err = os.OpenFile(logPath, os.O_WRONLY, 0); err != nil {
+		return err
+	}
+	// drops the torn record, if any, so that the next records follow the valid ones
+	if err = l.log.Truncate(valid); err != nil {
+		_ = l.log.Close()
+		return err
+	}
+	if _, err = l.log.Seek(valid, io.SeekStart); err != nil {
+		_ = l.log.Close()
+		return err
+	}
+	l.lastSync = time.Now()
+	return nil
+}
+
+// apply applies the record to the wrapped list
+func (l *List[T]) apply(r record[T]) (e T, ok bool) {
+	switch r.op {
+	case opAdd:
+		l.list.Add(r.elements...)
+	case opPrepend:
+		l.list.Prepend(r.elements...)
+	case opInsert:
+		return e, l.list.Insert(r.index, r.elements...)
+	case opSet:
+		return e, l.list.Set(r.index, r.elements[0])
+	case opRemove:
+		return l.list.Remove(r.index)
+	case opClear:
+		l.list.Clear()
+	case opReverse:
+		l.list.Reverse()
+	}
+	return e, true
+}
+
+// write appends the record to the log, syncs it according to the sync policy,
+// then applies it to the wrapped list and compacts the log if it is due
+func (l *List[T]) write(r record[T]) (e T, ok bool) {
+	if l.err != nil {
+		return e, false
+	}
+	if l.closed {
+		l.err = ErrClosed
+		return e, false
+	}
+	if err := l.append(r); err != nil {
+		l.err = err
+		return e, false
+	}
+	e, ok = l.apply(r)
+	if l.config.compactEvery > 0 && l.records >= l.config.compactEvery {
+		// a failed compaction that left the log intact is retried after the next mutation
+		_ = l.Compact()
+	}
+	return e, ok
+}
+
+func (l *List[T]) append(r record[T]) error {
+	payload, err := l.encoder.payload(r)
+	if err != nil {
+		return err
+	}
+	l.frame = appendFrame(l.frame[:0], payload)
+	if _, err = l.log.Write(l.frame); err != nil {
+		return err
+	}
+	l.records++
+	l.dirty = true
+	switch l.config.sync {
+	case SyncAlways:
+		return l.sync()
+	case SyncInterval:
+		if time.Since(l.lastSync) >= l.config.interval {
+			return l.sync()
+		}
+	}
+	return nil
+}
+
+func (l *List[T]) sync() error {
+	if !l.dirty {
+		return nil
+	}
+	if err := l.log.Sync(); err != nil {
+		return err
+	}
+	l.dirty = false
+	l.lastSync = time.Now()
+	return nil
+}
+
+// Sync makes the records appended to the log durable, whatever the sync policy
+func (l *List[T]) Sync() error {
+	if l.closed {
+		return ErrClosed
+	}
+	if err := l.sync(); err != nil {
+		l.err = err
+		return err
+	}
+	return nil
+}
+
+// Compact writes a snapshot of the elements and empties the log, so that the next Open replays fewer records
+func (l *List[T]) Compact() error {
+	if l.closed {
+		return ErrClosed
+	}
+	if l.err != nil {
+		return l.err
+	}
+	generation := l.generation + 1
+	if err := writeSnapshot(filepath.Join(l.dir, snapshotName), generation, l.list.Values(), l.codec); err != nil {
+		// the log is intact, the list can go on with it
+		return err
+	}
+	logPath := filepath.Join(l.dir, logName)
+	if err := writeEmptyLog(logPath, generation); err != nil {
+		l.err = err
+		return err
+	}
+	log, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		l.err = err
+		return err
+	}
+	_ = l.log.Close()
+	l.log = log
+	l.generation = generation
+	l.records = 0
+	l.dirty = false
+	return nil
+}
+
+// Err returns the error that made the list reject the mutations, if any
+func (l *List[T]) Err() error {
+	return l.err
+}
+
+// Close syncs and closes the log, the elements stay in the wrapped list
+func (l *List[T]) Close() error {
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	err := l.sync()
+	if cerr := l.log.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Add appends the elements to the end of the list
+func (l *List[T]) Add(elements ...T) {
+	if len(elements) > 0 {
+		l.write(record[T]{op: opAdd, elements: elements})
+	}
+}
+
+// Append appends the elements to the end of the list (same as Add)
+func (l *List[T]) Append(elements ...T) {
+	l.Add(elements...)
+}
+
+// Prepend prepends the elements to the beginning of the list
+func (l *List[T]) Prepend(elements ...T) {
+	if len(elements) > 0 {
+		l.write(record[T]{op: opPrepend, elements: elements})
+	}
+}
+
+// Set replaces the element at the index
+// If the index is out of range or the mutation cannot be logged, b return false
+func (l *List[T]) Set(index int, e T) bool {
+	if index < 0 || index >= l.list.Size() {
+		return false
+	}
+	_, ok := l.write(record[T]{op: opSet, index: index, elements: []T{e}})
+	return ok
+}
+
+// Insert inserts the elements at the index, see the wrapped list
+// If the index is out of range, the wrapped list rejects the elements or the mutation cannot be logged, b return false
+func (l *List[T]) Insert(index int, elements ...T) bool {
+	size := l.list.Size()
+	// index 0 is valid for an empty list
+	if (index < 0 || index >= size) && (index != 0 || size != 0) {
+		return false
+	}
+	_, ok := l.write(record[T]{op: opInsert, index: index, elements: elements})
+	return ok
+}
+
+// RemoveFirst removes the first element of the list and returns that element
+// If the list is empty or the mutation cannot be logged, b return false
+func (l *List[T]) RemoveFirst() (T, bool) {
+	return l.Remove(0)
+}
+
+// RemoveLast removes the last element of the list and returns that element
+// If the list is empty or the mutation cannot be logged, b return false
+func (l *List[T]) RemoveLast() (T, bool) {
+	return l.Remove(l.list.Size() - 1)
+}
+
+// Remove removes the element at the index and returns that element
+// If the index is out of range or the mutation cannot be logged, b return false
+func (l *List[T]) Remove(index int) (t T, b bool) {
+	if index < 0 || index >= l.list.Size() {
+		return
+	}
+	return l.write(record[T]{op: opRemove, index: index})
+}
+
+// Clear removes all the elements of the list
+func (l *List[T]) Clear() {
+	l.write(record[T]{op: opClear})
+}
+
+// Reverse reverses the order of the elements of the list
+func (l *List[T]) Reverse() {
+	l.write(record[T]{op: opReverse})
+}
+
+func (l *List[T]) GetFirst() (T, bool) {
+	return l.list.GetFirst()
+}
+
+func (l *List[T]) GetLast() (T, bool) {
+	return l.list.GetLast()
+}
+
+func (l *List[T]) Get(index int) (T, bool) {
+	return l.list.Get(index)
+}
+
+func (l *List[T]) IsEmpty() bool {
+	return l.list.IsEmpty()
+}
+
+func (l *List[T]) Size() int {
+	return l.list.Size()
+}
+
+func (l *List[T]) Values() []T {
+	return l.list.Values()
+}
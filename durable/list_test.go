@@ -0,0 +1,273 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package durable
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chenmingyong0423/algorithms/codec"
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func open(t *testing.T, dir string, opts ...Option) *List[int] {
+	l, err := Open[int](dir, linkedlist.NewDoublyLinkedList[int](), codec.GobCodec[int]{}, opts...)
+	require.NoError(t, err)
+	return l
+}
+
+// mutate applies every kind of mutation to the list
+func mutate(l linkedlist.LinkedList[int]) {
+	l.Add(1, 2, 3)
+	l.Append(4)
+	l.Prepend(0)
+	l.Insert(2, 10, 11)
+	l.Set(0, -1)
+	l.RemoveFirst()
+	l.RemoveLast()
+	l.Remove(1)
+	l.Reverse()
+	l.Add(5)
+}
+
+func TestList_Replay(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "sync always"},
+		{name: "sync interval", opts: []Option{WithSyncInterval(time.Hour)}},
+		{name: "sync never", opts: []Option{WithSyncPolicy(SyncNever)}},
+		{name: "compact", opts: []Option{WithCompactEvery(3)}},
+	}
+	want := linkedlist.NewDoublyLinkedList[int]()
+	mutate(want)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := open(t, dir, tc.opts...)
+			mutate(l)
+			assert.Equal(t, want.Values(), l.Values())
+			require.NoError(t, l.Close())
+
+			l = open(t, dir, tc.opts...)
+			assert.Equal(t, want.Values(), l.Values())
+			l.Clear()
+			l.Add(7)
+			require.NoError(t, l.Close())
+
+			l = open(t, dir, tc.opts...)
+			assert.Equal(t, []int{7}, l.Values())
+			assert.NoError(t, l.Close())
+		})
+	}
+}
+
+func TestList_Rejected(t *testing.T) {
+	dir := t.TempDir()
+	l := open(t, dir)
+	l.Add(1)
+	assert.False(t, l.Set(1, 2))
+	assert.False(t, l.Insert(2, 2))
+	_, ok := l.Remove(-1)
+	assert.False(t, ok)
+	l.Add()
+	// the mutations rejected up front are not logged
+	assert.Equal(t, 1, l.records)
+	require.NoError(t, l.Close())
+
+	// the mutations rejected by the wrapped list are rejected again by the replay
+	bounded := func() linkedlist.LinkedList[int] {
+		return linkedlist.NewSinglyLinkedListWithOptions[int](option.WithMaxSize[int](2))
+	}
+	l, err := Open[int](dir, bounded(), codec.GobCodec[int]{})
+	require.NoError(t, err)
+	assert.False(t, l.Insert(0, 2, 3))
+	l.Add(4)
+	require.NoError(t, l.Close())
+	l, err = Open[int](dir, bounded(), codec.GobCodec[int]{})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 4}, l.Values())
+	assert.NoError(t, l.Close())
+}
+
+func TestList_Compact(t *testing.T) {
+	dir := t.TempDir()
+	l := open(t, dir, WithCompactEvery(0))
+	for i := 0; i < 100; i++ {
+		l.Add(i)
+	}
+	before, err := os.Stat(filepath.Join(dir, logName))
+	require.NoError(t, err)
+	require.NoError(t, l.Compact())
+	after, err := os.Stat(filepath.Join(dir, logName))
+	require.NoError(t, err)
+	assert.Less(t, after.Size(), before.Size())
+	assert.Equal(t, int64(headerSize), after.Size())
+	l.RemoveFirst()
+	require.NoError(t, l.Close())
+
+	l = open(t, dir)
+	assert.Equal(t, 99, l.Size())
+	first, _ := l.GetFirst()
+	assert.Equal(t, 1, first)
+	assert.NoError(t, l.Close())
+}
+
+func TestList_CrashDuringCompaction(t *testing.T) {
+	dir := t.TempDir()
+	l := open(t, dir)
+	l.Add(1, 2)
+	require.NoError(t, l.Close())
+	log, err := os.ReadFile(filepath.Join(dir, logName))
+	require.NoError(t, err)
+
+	l = open(t, dir)
+	require.NoError(t, l.Compact())
+	require.NoError(t, l.Close())
+	// puts back the log of the previous generation, as if the crash happened before the empty log was written
+	require.NoError(t, os.WriteFile(filepath.Join(dir, logName), log, 0o644))
+
+	l = open(t, dir)
+	assert.Equal(t, []int{1, 2}, l.Values())
+	l.Add(3)
+	require.NoError(t, l.Close())
+	l = open(t, dir)
+	assert.Equal(t, []int{1, 2, 3}, l.Values())
+	assert.NoError(t, l.Close())
+}
+
+func TestList_TornRecord(t *testing.T) {
+	dir := t.TempDir()
+	l := open(t, dir)
+	l.Add(1)
+	l.Add(2)
+	require.NoError(t, l.Close())
+	path := filepath.Join(dir, logName)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data[:len(data)-2], 0o644))
+
+	l = open(t, dir)
+	assert.Equal(t, []int{1}, l.Values())
+	l.Add(3)
+	require.NoError(t, l.Close())
+	l = open(t, dir)
+	assert.Equal(t, []int{1, 3}, l.Values())
+	assert.NoError(t, l.Close())
+}
+
+func TestList_Corrupt(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		corrupt func(data []byte) []byte
+	}{
+		{
+			name: "log record",
+			file: logName,
+			corrupt: func(data []byte) []byte {
+				data[headerSize+frameSize] ^= 0xff
+				return data
+			},
+		},
+		{
+			name: "log record length",
+			file: logName,
+			corrupt: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[headerSize:], uint32(len(data)))
+				return data
+			},
+		},
+		{
+			name: "log header",
+			file: logName,
+			corrupt: func(data []byte) []byte {
+				data[0] = 'X'
+				return data
+			},
+		},
+		{
+			name: "snapshot",
+			file: snapshotName,
+			corrupt: func(data []byte) []byte {
+				data[len(data)-5] ^= 0xff
+				return data
+			},
+		},
+		{
+			name: "truncated snapshot",
+			file: snapshotName,
+			corrupt: func(data []byte) []byte {
+				return data[:headerSize]
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := open(t, dir)
+			l.Add(1, 2)
+			require.NoError(t, l.Compact())
+			l.Add(3)
+			l.Add(4)
+			require.NoError(t, l.Close())
+			path := filepath.Join(dir, tc.file)
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, tc.corrupt(data), 0o644))
+
+			_, err = Open[int](dir, linkedlist.NewDoublyLinkedList[int](), codec.GobCodec[int]{})
+			assert.ErrorIs(t, err, ErrCorrupt)
+		})
+	}
+}
+
+func TestList_Closed(t *testing.T) {
+	l := open(t, t.TempDir())
+	l.Add(1)
+	require.NoError(t, l.Close())
+	assert.NoError(t, l.Close())
+	l.Add(2)
+	assert.Equal(t, ErrClosed, l.Err())
+	assert.Equal(t, []int{1}, l.Values())
+	assert.Equal(t, ErrClosed, l.Sync())
+	assert.Equal(t, ErrClosed, l.Compact())
+}
+
+func TestList_Concurrent(t *testing.T) {
+	d := open(t, t.TempDir())
+	defer d.Close()
+	l := linkedlist.NewConcurrentLinkedList[int](d)
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func(i int) {
+			for j := 0; j < 25; j++ {
+				l.Add(i)
+			}
+			done <- struct{}{}
+		}(i)
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+	assert.Equal(t, 100, l.Size())
+}
@@ -0,0 +1,367 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package durable implements a linked list persisted to a write-ahead log,
+// so that its elements survive a crash of the process.
+package durable
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chenmingyong0423/algorithms/codec"
+)
+
+// ErrCorrupt is returned when the log or the snapshot fails its checks.
+var ErrCorrupt = errors.New("durable: corrupt log")
+
+// ErrClosed is returned by the operations on a closed list.
+var ErrClosed = errors.New("durable: closed")
+
+const (
+	logName      = "wal.log"
+	snapshotName = "snapshot.snap"
+)
+
+// The magics start the files, they end with the version of the format
+var (
+	logMagic      = [8]byte{'D', 'U', 'R', 'W', 'A', 'L', 0, 1}
+	snapshotMagic = [8]byte{'D', 'U', 'R', 'S', 'N', 'P', 0, 1}
+)
+
+// headerSize is the size of the magic and the generation starting both files
+const headerSize = 8 + 8
+
+// op is the kind of mutation recorded by the log
+type op byte
+
+const (
+	opAdd op = iota + 1
+	opPrepend
+	opInsert
+	opSet
+	opRemove
+	opClear
+	opReverse
+)
+
+// record is a mutation of the list, only the fields of its op are set
+type record[T any] struct {
+	op       op
+	index    int
+	elements []T
+}
+
+// encoder turns the records into their payload
+type encoder[T any] struct {
+	codec codec.Codec[T]
+	buf   []byte
+}
+
+// payload encodes the record as its op, the index for opInsert, opSet and opRemove,
+// and the elements for opAdd, opPrepend, opInsert and opSet, see appendElements.
+// The returned slice is only valid until the next call.
+func (e *encoder[T]) payload(r record[T]) ([]byte, error) {
+	e.buf = append(e.buf[:0], byte(r.op))
+	switch r.op {
+	case opInsert, opSet, opRemove:
+		e.buf = binary.AppendUvarint(e.buf, uint64(r.index))
+	}
+	switch r.op {
+	case opAdd, opPrepend, opInsert, opSet:
+		var err error
+		if e.buf, err = e.appendElements(e.buf, r.elements); err != nil {
+			return nil, err
+		}
+	}
+	return e.buf, nil
+}
+
+// appendElements appends the number of elements and every element prefixed by its length
+func (e *encoder[T]) appendElements(buf []byte, elements []T) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(len(elements)))
+	for _, element := range elements {
+		data, err := e.codec.Encode(element)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// decoder reads the records back from their payload
+type decoder[T any] struct {
+	codec codec.Codec[T]
+	data  []byte
+}
+
+func (d *decoder[T]) record() (r record[T], err error) {
+	if len(d.data) == 0 {
+		return r, errTruncated
+	}
+	r.op, d.data = op(d.data[0]), d.data[1:]
+	switch r.op {
+	case opAdd, opPrepend, opInsert, opSet, opRemove, opClear, opReverse:
+	default:
+		return r, fmt.Errorf("unknown op %d", r.op)
+	}
+	switch r.op {
+	case opInsert, opSet, opRemove:
+		index, err := d.uvarint()
+		if err != nil {
+			return r, err
+		}
+		r.index = int(index)
+	}
+	switch r.op {
+	case opAdd, opPrepend, opInsert, opSet:
+		if r.elements, err = d.elements(); err != nil {
+			return r, err
+		}
+	}
+	if len(d.data) != 0 {
+		return r, fmt.Errorf("%d trailing bytes", len(d.data))
+	}
+	return r, nil
+}
+
+func (d *decoder[T]) elements() ([]T, error) {
+	count, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	// every element takes at least one byte, this bounds the allocation of a damaged count
+	if count > uint64(len(d.data)) {
+		return nil, errTruncated
+	}
+	elements := make([]T, 0, count)
+	for i := uint64(0); i < count; i++ {
+		length, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(len(d.data)) {
+			return nil, errTruncated
+		}
+		e, err := d.codec.Decode(d.data[:length])
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, e)
+		d.data = d.data[length:]
+	}
+	return elements, nil
+}
+
+func (d *decoder[T]) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.data = d.data[n:]
+	return v, nil
+}
+
+var errTruncated = errors.New("truncated payload")
+
+// The log file is made of:
+//
+//	magic       [8]byte
+//	generation  uint64, little endian
+//	records, each one framed as:
+//	  length    uint32, little endian, of the payload
+//	  crc       uint32, little endian, CRC-32 (IEEE) of the payload
+//	  payload   see encoder.payload
+//
+// The snapshot file is made of:
+//
+//	magic       [8]byte
+//	generation  uint64, little endian
+//	elements    see encoder.appendElements
+//	crc         uint32, little endian, CRC-32 (IEEE) of all the bytes before it
+//
+// The log replays on top of the snapshot of the same generation.
+// Compacting writes the snapshot of the next generation before the empty log of that generation,
+// so a crash in between leaves a log of an older generation, which is ignored.
+
+// frameSize is the size of the length and the crc framing every record
+const frameSize = 4 + 4
+
+// appendFrame appends the framed payload to buf
+func appendFrame(buf, payload []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(payload)))
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(payload))
+	return append(buf, payload...)
+}
+
+// readLog reads the generation and the records of the log.
+// A torn record at the end of the log, left by a crash while appending it, is not an error:
+// valid is the size of the log without it.
+func readLog[T any](path string, codec codec.Codec[T]) (generation uint64, records []record[T], valid int64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	if len(data) < headerSize || !bytes.Equal(data[:len(logMagic)], logMagic[:]) {
+		return 0, nil, 0, fmt.Errorf("%w: %s: bad header", ErrCorrupt, path)
+	}
+	generation = binary.LittleEndian.Uint64(data[len(logMagic):])
+	offset := headerSize
+	for offset < len(data) {
+		rest := data[offset:]
+		if len(rest) < frameSize {
+			break
+		}
+		length := int64(binary.LittleEndian.Uint32(rest))
+		if length > int64(len(rest)-frameSize) {
+			if holdsFrame(rest[frameSize:]) {
+				// a frame follows, so the length itself is damaged
+				return 0, nil, 0, fmt.Errorf("%w: %s: bad length at offset %d", ErrCorrupt, path, offset)
+			}
+			// the last record was not fully written
+			break
+		}
+		payload := rest[frameSize : frameSize+length]
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(rest[4:]) {
+			if frameSize+int(length) == len(rest) {
+				// the last record was not fully written
+				break
+			}
+			return 0, nil, 0, fmt.Errorf("%w: %s: checksum mismatch at offset %d", ErrCorrupt, path, offset)
+		}
+		d := decoder[T]{codec: codec, data: payload}
+		r, err := d.record()
+		if err != nil {
+			return 0, nil, 0, fmt.Errorf("%w: %s: record at offset %d: %v", ErrCorrupt, path, offset, err)
+		}
+		records = append(records, r)
+		offset += frameSize + int(length)
+	}
+	return generation, records, int64(offset), nil
+}
+
+// holdsFrame reports whether a complete record with a valid checksum starts anywhere in data,
+// which tells a damaged frame in the middle of the log from a torn one at its end
+func holdsFrame(data []byte) bool {
+	for i := 0; i+frameSize < len(data); i++ {
+		length := int64(binary.LittleEndian.Uint32(data[i:]))
+		// the records are never empty, they start with their op
+		if length == 0 || length > int64(len(data)-i-frameSize) {
+			continue
+		}
+		payload := data[i+frameSize : i+frameSize+int(length)]
+		if crc32.ChecksumIEEE(payload) == binary.LittleEndian.Uint32(data[i+4:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// readSnapshot reads the generation and the elements of the snapshot
+func readSnapshot[T any](path string, codec codec.Codec[T]) (generation uint64, elements []T, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	const crcSize = 4
+	if len(data) < headerSize+crcSize || !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic[:]) {
+		return 0, nil, fmt.Errorf("%w: %s: bad header", ErrCorrupt, path)
+	}
+	body, sum := data[:len(data)-crcSize], binary.LittleEndian.Uint32(data[len(data)-crcSize:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return 0, nil, fmt.Errorf("%w: %s: checksum mismatch", ErrCorrupt, path)
+	}
+	d := decoder[T]{codec: codec, data: body[headerSize:]}
+	if elements, err = d.elements(); err == nil && len(d.data) != 0 {
+		err = fmt.Errorf("%d trailing bytes", len(d.data))
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, path, err)
+	}
+	return binary.LittleEndian.Uint64(body[len(snapshotMagic):]), elements, nil
+}
+
+// writeAtomically writes a new file through a synced temporary file renamed over path
+func writeAtomically(path string, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	w := bufio.NewWriter(tmp)
+	if err = write(w); err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir makes the entries created or renamed in the directory durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// writeSnapshot writes the snapshot of the elements for the generation
+func writeSnapshot[T any](path string, generation uint64, elements []T, codec codec.Codec[T]) error {
+	e := encoder[T]{codec: codec}
+	buf := binary.LittleEndian.AppendUint64(snapshotMagic[:], generation)
+	buf, err := e.appendElements(buf, elements)
+	if err != nil {
+		return err
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+	return writeAtomically(path, func(w io.Writer) error {
+		_, err := w.Write(buf)
+		return err
+	})
+}
+
+// writeEmptyLog writes a log without records for the generation
+func writeEmptyLog(path string, generation uint64) error {
+	return writeAtomically(path, func(w io.Writer) error {
+		_, err := w.Write(binary.LittleEndian.AppendUint64(logMagic[:], generation))
+		return err
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package durable
+
+import "time"
+
+// SyncPolicy decides when the records appended to the log are synced to disk.
+type SyncPolicy int
+
+const (
+	// SyncAlways syncs every record before the mutation is applied, the mutations survive a crash of the machine.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval syncs a record if the last sync is older than the interval,
+	// the mutations of the last interval may be lost if the machine crashes.
+	SyncInterval
+	// SyncNever leaves the syncs to the operating system and to List.Sync,
+	// the mutations survive a crash of the process but not of the machine.
+	SyncNever
+)
+
+// Option configures a List.
+type Option func(c *config)
+
+type config struct {
+	sync         SyncPolicy
+	interval     time.Duration
+	compactEvery int
+}
+
+// WithSyncPolicy sets the sync policy, SyncAlways by default.
+func WithSyncPolicy(policy SyncPolicy) Option {
+	return func(c *config) {
+		c.sync = policy
+	}
+}
+
+// WithSyncInterval sets the SyncInterval policy with the interval, one second by default.
+func WithSyncInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.sync = SyncInterval
+		c.interval = interval
+	}
+}
+
+// WithCompactEvery compacts the log once it holds n records, 10000 by default, zero or less disables it.
+func WithCompactEvery(n int) Option {
+	return func(c *config) {
+		c.compactEvery = n
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{compactEvery: 10000}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.sync == SyncInterval && c.interval <= 0 {
+		c.interval = time.Second
+	}
+	return c
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmpty is returned when an element is requested from an empty collection.
+	ErrEmpty = errors.New("algorithms: the collection is empty")
+	// ErrFull is returned when an element is rejected because the collection reached its max size.
+	ErrFull = errors.New("algorithms: the collection is full")
+)
+
+// ErrIndexOutOfRange is returned when an index is out of the range of a collection.
+// errors.Is matches it with any ErrIndexOutOfRange, e.g. errs.ErrIndexOutOfRange{},
+// errors.As retrieves the index and the size.
+type ErrIndexOutOfRange struct {
+	Index int
+	Size  int
+}
+
+func (e ErrIndexOutOfRange) Error() string {
+	return fmt.Sprintf("algorithms: index %d out of range [0, %d)", e.Index, e.Size)
+}
+
+// Is reports whether target is an ErrIndexOutOfRange, whatever its index and size
+func (ErrIndexOutOfRange) Is(target error) bool {
+	_, ok := target.(ErrIndexOutOfRange)
+	return ok
+}
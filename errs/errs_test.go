@@ -0,0 +1,35 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrIndexOutOfRange(t *testing.T) {
+	err := fmt.Errorf("get: %w", ErrIndexOutOfRange{Index: 3, Size: 2})
+	assert.Equal(t, "get: algorithms: index 3 out of range [0, 2)", err.Error())
+
+	var target ErrIndexOutOfRange
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, ErrIndexOutOfRange{Index: 3, Size: 2}, target)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange{Index: 3, Size: 2}))
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange{}))
+	assert.False(t, errors.Is(err, ErrEmpty))
+}
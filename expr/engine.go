@@ -0,0 +1,244 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package expr parses infix expressions into postfix notation with the shunting-yard algorithm
+// and evaluates them, with configurable operators, functions, constants and variables.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrDivisionByZero is returned when dividing by zero.
+	ErrDivisionByZero = errors.New("expr: division by zero")
+	// ErrUndefinedVariable is returned when a variable has no value.
+	ErrUndefinedVariable = errors.New("expr: undefined variable")
+)
+
+// Operator is a prefix unary or an infix binary operator.
+type Operator struct {
+	Symbol string
+	// Precedence orders the operators, the higher binds tighter
+	Precedence int
+	// RightAssoc makes a binary operator right associative, e.g. 2 ^ 3 ^ 2 is 2 ^ (3 ^ 2)
+	RightAssoc bool
+	// Unary makes the operator a prefix unary operator, a symbol can be both unary and binary
+	Unary bool
+	// Func computes the operator from its operands
+	Func func(args []float64) (float64, error)
+}
+
+// Function is a function called as name(arg, ...).
+type Function struct {
+	Name string
+	// Arity is the number of arguments, a negative arity accepts one or more arguments
+	Arity int
+	// Func computes the function from its arguments
+	Func func(args []float64) (float64, error)
+}
+
+// Option configures an Engine.
+type Option func(e *Engine)
+
+// WithOperator adds the operator or replaces the one with the same symbol and arity.
+// It panics if the symbol is empty or contains letters, digits, spaces, dots, underscores, parentheses or commas.
+func WithOperator(op Operator) Option {
+	if op.Symbol == "" {
+		panic("expr: empty operator symbol")
+	}
+	for _, r := range op.Symbol {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || strings.ContainsRune("(),._", r) {
+			panic(fmt.Sprintf("expr: invalid operator symbol %q", op.Symbol))
+		}
+	}
+	return func(e *Engine) {
+		if op.Unary {
+			e.unary[op.Symbol] = op
+		} else {
+			e.binary[op.Symbol] = op
+		}
+	}
+}
+
+// WithFunction adds the function or replaces the one with the same name.
+func WithFunction(f Function) Option {
+	return func(e *Engine) {
+		e.functions[f.Name] = f
+	}
+}
+
+// WithConstant adds a constant, variables passed to Eval take precedence over constants.
+func WithConstant(name string, value float64) Option {
+	return func(e *Engine) {
+		e.constants[name] = value
+	}
+}
+
+// WithoutDefaults removes the default operators, functions and constants.
+func WithoutDefaults() Option {
+	return func(e *Engine) {
+		e.noDefaults = true
+	}
+}
+
+// Engine parses and evaluates expressions, it is safe for concurrent use once built.
+type Engine struct {
+	unary      map[string]Operator
+	binary     map[string]Operator
+	functions  map[string]Function
+	constants  map[string]float64
+	noDefaults bool
+	// symbols holds the operator symbols, the longest first
+	symbols []string
+}
+
+// New returns a new Engine configured by opts.
+// The default operators are + - * / % and the right associative ^, and the unary - and +.
+// The default functions are abs, sqrt, exp, log, sin, cos, tan, pow, min and max,
+// and the default constants are pi and e.
+func New(opts ...Option) *Engine {
+	e := &Engine{
+		unary:     make(map[string]Operator),
+		binary:    make(map[string]Operator),
+		functions: make(map[string]Function),
+		constants: make(map[string]float64),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if !e.noDefaults {
+		e.addDefaults()
+	}
+	seen := make(map[string]bool)
+	for _, ops := range []map[string]Operator{e.unary, e.binary} {
+		for symbol := range ops {
+			if !seen[symbol] {
+				seen[symbol] = true
+				e.symbols = append(e.symbols, symbol)
+			}
+		}
+	}
+	sort.Slice(e.symbols, func(i, j int) bool {
+		if len(e.symbols[i]) != len(e.symbols[j]) {
+			return len(e.symbols[i]) > len(e.symbols[j])
+		}
+		return e.symbols[i] < e.symbols[j]
+	})
+	return e
+}
+
+// Eval parses and evaluates the expression with the variables.
+func (e *Engine) Eval(s string, vars map[string]float64) (float64, error) {
+	x, err := e.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return x.Eval(vars)
+}
+
+// addDefaults adds the default operators, functions and constants which have not been configured
+func (e *Engine) addDefaults() {
+	for _, op := range defaultOperators {
+		ops := e.binary
+		if op.Unary {
+			ops = e.unary
+		}
+		if _, ok := ops[op.Symbol]; !ok {
+			ops[op.Symbol] = op
+		}
+	}
+	for _, f := range defaultFunctions {
+		if _, ok := e.functions[f.Name]; !ok {
+			e.functions[f.Name] = f
+		}
+	}
+	for name, v := range map[string]float64{"pi": math.Pi, "e": math.E} {
+		if _, ok := e.constants[name]; !ok {
+			e.constants[name] = v
+		}
+	}
+}
+
+var defaultOperators = []Operator{
+	{Symbol: "+", Precedence: 1, Func: func(args []float64) (float64, error) {
+		return args[0] + args[1], nil
+	}},
+	{Symbol: "-", Precedence: 1, Func: func(args []float64) (float64, error) {
+		return args[0] - args[1], nil
+	}},
+	{Symbol: "*", Precedence: 2, Func: func(args []float64) (float64, error) {
+		return args[0] * args[1], nil
+	}},
+	{Symbol: "/", Precedence: 2, Func: func(args []float64) (float64, error) {
+		if args[1] == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return args[0] / args[1], nil
+	}},
+	{Symbol: "%", Precedence: 2, Func: func(args []float64) (float64, error) {
+		if args[1] == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return math.Mod(args[0], args[1]), nil
+	}},
+	// the unary operators bind looser than ^ so that -2 ^ 2 is -(2 ^ 2)
+	{Symbol: "-", Precedence: 3, Unary: true, Func: func(args []float64) (float64, error) {
+		return -args[0], nil
+	}},
+	{Symbol: "+", Precedence: 3, Unary: true, Func: func(args []float64) (float64, error) {
+		return args[0], nil
+	}},
+	{Symbol: "^", Precedence: 4, RightAssoc: true, Func: func(args []float64) (float64, error) {
+		return math.Pow(args[0], args[1]), nil
+	}},
+}
+
+var defaultFunctions = []Function{
+	mathFunction("abs", math.Abs),
+	mathFunction("sqrt", math.Sqrt),
+	mathFunction("exp", math.Exp),
+	mathFunction("log", math.Log),
+	mathFunction("sin", math.Sin),
+	mathFunction("cos", math.Cos),
+	mathFunction("tan", math.Tan),
+	{Name: "pow", Arity: 2, Func: func(args []float64) (float64, error) {
+		return math.Pow(args[0], args[1]), nil
+	}},
+	{Name: "min", Arity: -1, Func: func(args []float64) (float64, error) {
+		m := args[0]
+		for _, v := range args[1:] {
+			m = math.Min(m, v)
+		}
+		return m, nil
+	}},
+	{Name: "max", Arity: -1, Func: func(args []float64) (float64, error) {
+		m := args[0]
+		for _, v := range args[1:] {
+			m = math.Max(m, v)
+		}
+		return m, nil
+	}},
+}
+
+func mathFunction(name string, f func(float64) float64) Function {
+	return Function{Name: name, Arity: 1, Func: func(args []float64) (float64, error) {
+		return f(args[0]), nil
+	}}
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	boolean := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	e := New(
+		WithOperator(Operator{Symbol: "<", Precedence: 0, Func: func(args []float64) (float64, error) {
+			return boolean(args[0] < args[1]), nil
+		}}),
+		WithOperator(Operator{Symbol: "!", Precedence: 5, Unary: true, Func: func(args []float64) (float64, error) {
+			return boolean(args[0] == 0), nil
+		}}),
+		// makes the default ^ left associative
+		WithOperator(Operator{Symbol: "^", Precedence: 4, Func: func(args []float64) (float64, error) {
+			return args[0] - args[1], nil
+		}}),
+		WithConstant("answer", 42),
+	)
+	got, err := e.Eval("1 + 1 < answer", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), got)
+	got, err = e.Eval("!0 + !2", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), got)
+	got, err = e.Eval("8 ^ 2 ^ 1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), got)
+}
+
+func TestNew_WithoutDefaults(t *testing.T) {
+	e := New(WithoutDefaults(), WithOperator(Operator{Symbol: "+", Precedence: 1, Func: func(args []float64) (float64, error) {
+		return args[0] + args[1], nil
+	}}))
+	got, err := e.Eval("1 + 2", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), got)
+	_, err = e.Parse("1 * 2")
+	assert.EqualError(t, err, "expr: unexpected character '*' at position 2")
+	_, err = e.Parse("+1")
+	assert.EqualError(t, err, `expr: unexpected operator "+" at position 0`)
+	_, err = e.Parse("abs(1)")
+	assert.EqualError(t, err, `expr: unknown function "abs" at position 0`)
+	_, err = e.Eval("pi", nil)
+	assert.ErrorIs(t, err, ErrUndefinedVariable)
+}
+
+func TestWithOperator_Invalid(t *testing.T) {
+	for _, symbol := range []string{"", "and", "+1", "(", ",", "a b"} {
+		assert.Panics(t, func() {
+			WithOperator(Operator{Symbol: symbol})
+		}, symbol)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError is returned when an expression cannot be parsed.
+type SyntaxError struct {
+	// Input is the parsed expression
+	Input string
+	// Pos is the byte offset of the offending token in Input
+	Pos int
+	// Token is the offending token, empty at the end of the input
+	Token string
+	Msg   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("expr: %s at position %d", e.Msg, e.Pos)
+}
+
+// Pointer returns the input and a caret under the offending token on the next line, e.g.
+//
+//	1 + * 2
+//	    ^
+func (e *SyntaxError) Pointer() string {
+	// the caret is aligned on runes, not bytes
+	return e.Input + "\n" + strings.Repeat(" ", len([]rune(e.Input[:e.Pos]))) + "^"
+}
+
+// EvalError is returned when an expression cannot be evaluated.
+type EvalError struct {
+	// Pos is the byte offset of the token which failed in the parsed expression
+	Pos   int
+	Token string
+	Err   error
+}
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("%v: %q at position %d", e.Err, e.Token, e.Pos)
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind is the kind of a Token.
+type TokenKind int
+
+const (
+	// TokenNumber is a number literal
+	TokenNumber TokenKind = iota
+	// TokenIdent is a variable or a constant, and a function name before it is parsed
+	TokenIdent
+	// TokenBinary is an infix operator, and any operator before it is parsed
+	TokenBinary
+	// TokenUnary is a prefix operator
+	TokenUnary
+	// TokenFunction is a function call
+	TokenFunction
+	TokenLeftParen
+	TokenRightParen
+	TokenComma
+)
+
+// Token is a lexical token of an expression.
+type Token struct {
+	Kind TokenKind
+	Text string
+	// Pos is the byte offset of the token in the expression
+	Pos int
+	// Value is the value of a Number
+	Value float64
+	// Args is the number of arguments of a Function
+	Args int
+}
+
+func (t Token) String() string {
+	if t.Kind == TokenFunction {
+		return fmt.Sprintf("%s/%d", t.Text, t.Args)
+	}
+	return t.Text
+}
+
+// Tokenize splits the expression into tokens, the operators are matched to the longest symbol.
+func (e *Engine) Tokenize(s string) ([]Token, error) {
+	var tokens []Token
+	for pos := 0; pos < len(s); {
+		r, size := utf8.DecodeRuneInString(s[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+			continue
+		case r == '(':
+			tokens = append(tokens, Token{Kind: TokenLeftParen, Text: "(", Pos: pos})
+		case r == ')':
+			tokens = append(tokens, Token{Kind: TokenRightParen, Text: ")", Pos: pos})
+		case r == ',':
+			tokens = append(tokens, Token{Kind: TokenComma, Text: ",", Pos: pos})
+		case r >= '0' && r <= '9' || r == '.':
+			text := scanNumber(s[pos:])
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, &SyntaxError{Input: s, Pos: pos, Token: text, Msg: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, Token{Kind: TokenNumber, Text: text, Pos: pos, Value: v})
+			pos += len(text)
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			end := pos + size
+			for end < len(s) {
+				r, size := utf8.DecodeRuneInString(s[end:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, Token{Kind: TokenIdent, Text: s[pos:end], Pos: pos})
+			pos = end
+			continue
+		default:
+			symbol, ok := e.matchSymbol(s[pos:])
+			if !ok {
+				return nil, &SyntaxError{Input: s, Pos: pos, Token: string(r), Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, Token{Kind: TokenBinary, Text: symbol, Pos: pos})
+			pos += len(symbol)
+			continue
+		}
+		pos += size
+	}
+	return tokens, nil
+}
+
+// matchSymbol returns the longest operator symbol prefixing s
+func (e *Engine) matchSymbol(s string) (string, bool) {
+	for _, symbol := range e.symbols {
+		if strings.HasPrefix(s, symbol) {
+			return symbol, true
+		}
+	}
+	return "", false
+}
+
+// scanNumber returns the number literal prefixing s: digits, a fraction and an exponent
+func scanNumber(s string) string {
+	i := 0
+	digits := func() {
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	}
+	digits()
+	if i < len(s) && s[i] == '.' {
+		i++
+		digits()
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		// the exponent is part of the number only if digits follow, 2e is 2 followed by e
+		if j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			i = j
+			digits()
+		}
+	}
+	return s[:i]
+}
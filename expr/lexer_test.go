@@ -0,0 +1,100 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_Tokenize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		engine   *Engine
+		input    string
+		wantText []string
+		wantKind []TokenKind
+		wantPos  []int
+		wantErr  string
+	}{
+		{
+			name:     "numbers and operators",
+			engine:   New(),
+			input:    "1.5e3 + .5*x_1",
+			wantText: []string{"1.5e3", "+", ".5", "*", "x_1"},
+			wantKind: []TokenKind{TokenNumber, TokenBinary, TokenNumber, TokenBinary, TokenIdent},
+			wantPos:  []int{0, 6, 8, 10, 11},
+		},
+		{
+			name:     "exponent without digits",
+			engine:   New(),
+			input:    "2e",
+			wantText: []string{"2", "e"},
+			wantKind: []TokenKind{TokenNumber, TokenIdent},
+			wantPos:  []int{0, 1},
+		},
+		{
+			name:     "function call",
+			engine:   New(),
+			input:    "max(a, 2)",
+			wantText: []string{"max", "(", "a", ",", "2", ")"},
+			wantKind: []TokenKind{TokenIdent, TokenLeftParen, TokenIdent, TokenComma, TokenNumber, TokenRightParen},
+			wantPos:  []int{0, 3, 4, 5, 7, 8},
+		},
+		{
+			name: "longest symbol",
+			engine: New(WithOperator(Operator{Symbol: "**", Precedence: 4, RightAssoc: true}),
+				WithOperator(Operator{Symbol: "*=*", Precedence: 1})),
+			input:    "2**3*=*4*5",
+			wantText: []string{"2", "**", "3", "*=*", "4", "*", "5"},
+			wantKind: []TokenKind{TokenNumber, TokenBinary, TokenNumber, TokenBinary, TokenNumber, TokenBinary, TokenNumber},
+			wantPos:  []int{0, 1, 3, 4, 7, 8, 9},
+		},
+		{
+			name:    "unexpected character",
+			engine:  New(),
+			input:   "1 # 2",
+			wantErr: `expr: unexpected character '#' at position 2`,
+		},
+		{
+			name:    "invalid number",
+			engine:  New(),
+			input:   "1 + .",
+			wantErr: `expr: invalid number "." at position 4`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens, err := tc.engine.Tokenize(tc.input)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			var texts []string
+			var kinds []TokenKind
+			var positions []int
+			for _, token := range tokens {
+				texts = append(texts, token.Text)
+				kinds = append(kinds, token.Kind)
+				positions = append(positions, token.Pos)
+			}
+			assert.Equal(t, tc.wantText, texts)
+			assert.Equal(t, tc.wantKind, kinds)
+			assert.Equal(t, tc.wantPos, positions)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/chenmingyong0423/algorithms/stack"
+)
+
+// Expression is a parsed expression in postfix notation.
+type Expression struct {
+	engine  *Engine
+	postfix []Token
+}
+
+// paren is an open parenthesis, possibly of a function call
+type paren struct {
+	Token
+	call bool
+	args int
+}
+
+// Parse parses the infix expression into postfix notation with the shunting-yard algorithm.
+func (e *Engine) Parse(s string) (*Expression, error) {
+	tokens, err := e.Tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	syntaxError := func(t Token, format string, args ...any) error {
+		return &SyntaxError{Input: s, Pos: t.Pos, Token: t.Text, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	var output []Token
+	// operators holds the operators, functions and parentheses waiting for their operands
+	operators := stack.NewArrayStack[Token]()
+	parens := stack.NewArrayStack[paren]()
+	// expectOperand tells whether an operand or a prefix operator is expected, rather than an infix operator
+	expectOperand := true
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch t.Kind {
+		case TokenNumber:
+			if !expectOperand {
+				return nil, syntaxError(t, "unexpected number %q", t.Text)
+			}
+			output = append(output, t)
+			expectOperand = false
+		case TokenIdent:
+			if !expectOperand {
+				return nil, syntaxError(t, "unexpected identifier %q", t.Text)
+			}
+			_, isFunction := e.functions[t.Text]
+			if i+1 < len(tokens) && tokens[i+1].Kind == TokenLeftParen {
+				if !isFunction {
+					return nil, syntaxError(t, "unknown function %q", t.Text)
+				}
+				t.Kind = TokenFunction
+				operators.Push(t)
+				continue
+			}
+			if isFunction {
+				return nil, syntaxError(t, "missing ( after function %q", t.Text)
+			}
+			output = append(output, t)
+			expectOperand = false
+		case TokenBinary:
+			if expectOperand {
+				if _, ok := e.unary[t.Text]; !ok {
+					return nil, syntaxError(t, "unexpected operator %q", t.Text)
+				}
+				// a prefix operator applies to the operand which follows, nothing to pop
+				t.Kind = TokenUnary
+				operators.Push(t)
+				continue
+			}
+			op, ok := e.binary[t.Text]
+			if !ok {
+				return nil, syntaxError(t, "operator %q is not binary", t.Text)
+			}
+			for {
+				top, ok := operators.Peek()
+				if !ok || top.Kind != TokenBinary && top.Kind != TokenUnary {
+					break
+				}
+				precedence := e.operator(top).Precedence
+				if precedence < op.Precedence || precedence == op.Precedence && op.RightAssoc {
+					break
+				}
+				operators.Pop()
+				output = append(output, top)
+			}
+			operators.Push(t)
+			expectOperand = true
+		case TokenLeftParen:
+			if !expectOperand {
+				return nil, syntaxError(t, "unexpected (")
+			}
+			p := paren{Token: t}
+			if top, ok := operators.Peek(); ok && top.Kind == TokenFunction {
+				p.call = true
+				if i+1 < len(tokens) && tokens[i+1].Kind != TokenRightParen {
+					p.args = 1
+				}
+			}
+			operators.Push(t)
+			parens.Push(p)
+		case TokenComma:
+			p, ok := parens.Peek()
+			if !ok || !p.call {
+				return nil, syntaxError(t, "unexpected , outside of a function call")
+			}
+			if expectOperand {
+				return nil, syntaxError(t, "missing argument before ,")
+			}
+			output = popUntilParen(operators, output)
+			parens.Pop()
+			p.args++
+			parens.Push(p)
+			expectOperand = true
+		case TokenRightParen:
+			p, ok := parens.Pop()
+			if !ok {
+				return nil, syntaxError(t, "unmatched )")
+			}
+			// only the parentheses of a call without arguments can be empty
+			if expectOperand && (!p.call || p.args > 0) {
+				return nil, syntaxError(t, "unexpected )")
+			}
+			output = popUntilParen(operators, output)
+			operators.Pop()
+			if p.call {
+				fn, _ := operators.Pop()
+				f := e.functions[fn.Text]
+				if f.Arity >= 0 && p.args != f.Arity || f.Arity < 0 && p.args == 0 {
+					return nil, syntaxError(fn, "function %q expects %s, got %d", fn.Text, arguments(f.Arity), p.args)
+				}
+				fn.Args = p.args
+				output = append(output, fn)
+			}
+			expectOperand = false
+		}
+	}
+	if expectOperand {
+		return nil, &SyntaxError{Input: s, Pos: len(s), Msg: "unexpected end of expression"}
+	}
+	for !operators.IsEmpty() {
+		t, _ := operators.Pop()
+		if t.Kind == TokenLeftParen {
+			return nil, syntaxError(t, "unclosed (")
+		}
+		output = append(output, t)
+	}
+	return &Expression{engine: e, postfix: output}, nil
+}
+
+// popUntilParen moves the operators above the innermost open parenthesis to the output
+func popUntilParen(operators *stack.ArrayStack[Token], output []Token) []Token {
+	for {
+		top, _ := operators.Peek()
+		if top.Kind == TokenLeftParen {
+			return output
+		}
+		operators.Pop()
+		output = append(output, top)
+	}
+}
+
+// operator returns the operator of the token
+func (e *Engine) operator(t Token) Operator {
+	if t.Kind == TokenUnary {
+		return e.unary[t.Text]
+	}
+	return e.binary[t.Text]
+}
+
+func arguments(arity int) string {
+	switch {
+	case arity < 0:
+		return "at least 1 argument"
+	case arity == 1:
+		return "1 argument"
+	default:
+		return fmt.Sprintf("%d arguments", arity)
+	}
+}
+
+// Postfix returns the tokens of the expression in postfix notation.
+func (x *Expression) Postfix() []Token {
+	return append([]Token(nil), x.postfix...)
+}
+
+// String returns the expression in postfix notation, e.g. "1 2 3 * +",
+// functions are followed by their number of arguments, e.g. "1 2 max/2".
+func (x *Expression) String() string {
+	texts := make([]string, len(x.postfix))
+	for i, t := range x.postfix {
+		texts[i] = t.String()
+	}
+	return strings.Join(texts, " ")
+}
+
+// Eval evaluates the expression with the variables.
+func (x *Expression) Eval(vars map[string]float64) (float64, error) {
+	operands := stack.NewArrayStack[float64](option.WithCapacity[float64](len(x.postfix)))
+	pop := func(n int) []float64 {
+		args := make([]float64, n)
+		for i := n - 1; i >= 0; i-- {
+			args[i], _ = operands.Pop()
+		}
+		return args
+	}
+	for _, t := range x.postfix {
+		var (
+			v   float64
+			err error
+		)
+		switch t.Kind {
+		case TokenNumber:
+			v = t.Value
+		case TokenIdent:
+			var ok bool
+			if v, ok = vars[t.Text]; !ok {
+				if v, ok = x.engine.constants[t.Text]; !ok {
+					err = ErrUndefinedVariable
+				}
+			}
+		case TokenUnary:
+			v, err = x.engine.unary[t.Text].Func(pop(1))
+		case TokenBinary:
+			v, err = x.engine.binary[t.Text].Func(pop(2))
+		case TokenFunction:
+			v, err = x.engine.functions[t.Text].Func(pop(t.Args))
+		}
+		if err != nil {
+			return 0, &EvalError{Pos: t.Pos, Token: t.Text, Err: err}
+		}
+		operands.Push(v)
+	}
+	v, _ := operands.Pop()
+	return v, nil
+}
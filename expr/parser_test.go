@@ -0,0 +1,239 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_Parse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		wantPostfix string
+		wantErr     string
+		wantPointer string
+	}{
+		{
+			name:        "precedence",
+			input:       "1 + 2 * 3 - 4",
+			wantPostfix: "1 2 3 * + 4 -",
+		},
+		{
+			name:        "right associativity",
+			input:       "2 ^ 3 ^ 2",
+			wantPostfix: "2 3 2 ^ ^",
+		},
+		{
+			name:        "parentheses",
+			input:       "(1 + 2) * 3",
+			wantPostfix: "1 2 + 3 *",
+		},
+		{
+			name:        "unary operators",
+			input:       "-2 ^ 2 * -+x",
+			wantPostfix: "2 2 ^ - x + - *",
+		},
+		{
+			name:        "functions",
+			input:       "max(1, sin(x) + 1, pow(2, 3))",
+			wantPostfix: "1 x sin/1 1 + 2 3 pow/2 max/3",
+		},
+		{
+			name:        "function without arguments",
+			input:       "rand() * 2",
+			wantPostfix: "rand/0 2 *",
+		},
+		{
+			name:        "missing operand",
+			input:       "1 + * 2",
+			wantErr:     `expr: unexpected operator "*" at position 4`,
+			wantPointer: "1 + * 2\n    ^",
+		},
+		{
+			name:    "missing operator",
+			input:   "1 2",
+			wantErr: `expr: unexpected number "2" at position 2`,
+		},
+		{
+			name:        "unclosed parenthesis",
+			input:       "(1 + (2)",
+			wantErr:     "expr: unclosed ( at position 0",
+			wantPointer: "(1 + (2)\n^",
+		},
+		{
+			name:    "unmatched parenthesis",
+			input:   "1 + 2)",
+			wantErr: "expr: unmatched ) at position 5",
+		},
+		{
+			name:    "empty parentheses",
+			input:   "()",
+			wantErr: "expr: unexpected ) at position 1",
+		},
+		{
+			name:        "end of expression",
+			input:       "1 +",
+			wantErr:     "expr: unexpected end of expression at position 3",
+			wantPointer: "1 +\n   ^",
+		},
+		{
+			name:    "unknown function",
+			input:   "foo(1)",
+			wantErr: `expr: unknown function "foo" at position 0`,
+		},
+		{
+			name:    "function without parentheses",
+			input:   "sin + 1",
+			wantErr: `expr: missing ( after function "sin" at position 0`,
+		},
+		{
+			name:    "wrong number of arguments",
+			input:   "1 + pow(1)",
+			wantErr: `expr: function "pow" expects 2 arguments, got 1 at position 4`,
+		},
+		{
+			name:    "variadic function without arguments",
+			input:   "max()",
+			wantErr: `expr: function "max" expects at least 1 argument, got 0 at position 0`,
+		},
+		{
+			name:    "missing argument",
+			input:   "max(1,,2)",
+			wantErr: "expr: missing argument before , at position 6",
+		},
+		{
+			name:    "trailing comma",
+			input:   "max(1,)",
+			wantErr: "expr: unexpected ) at position 6",
+		},
+		{
+			name:    "comma outside of a call",
+			input:   "(1, 2)",
+			wantErr: "expr: unexpected , outside of a function call at position 2",
+		},
+		{
+			name:        "position in runes",
+			input:       "π + * 1",
+			wantErr:     `expr: unexpected operator "*" at position 5`,
+			wantPointer: "π + * 1\n    ^",
+		},
+	}
+	e := New(WithFunction(Function{Name: "rand", Func: func(args []float64) (float64, error) {
+		return 0.5, nil
+	}}))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			x, err := e.Parse(tc.input)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				var syntaxErr *SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+				if tc.wantPointer != "" {
+					assert.Equal(t, tc.wantPointer, syntaxErr.Pointer())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantPostfix, x.String())
+		})
+	}
+}
+
+func TestExpression_Eval(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		vars    map[string]float64
+		want    float64
+		wantErr error
+		wantPos int
+	}{
+		{
+			name:  "arithmetic",
+			input: "1 + 2 * 3 - 8 / 4 % 3",
+			want:  5,
+		},
+		{
+			name:  "power",
+			input: "2 ^ 3 ^ 2",
+			want:  512,
+		},
+		{
+			name:  "unary minus",
+			input: "-2 ^ 2 + -(-3)",
+			want:  -1,
+		},
+		{
+			name:  "functions",
+			input: "max(1, abs(-7), pow(2, 2)) + min(3) + sqrt(16)",
+			want:  14,
+		},
+		{
+			name:  "variables and constants",
+			input: "x * y + cos(pi)",
+			vars:  map[string]float64{"x": 2, "y": 3},
+			want:  5,
+		},
+		{
+			name:  "variables shadow constants",
+			input: "e",
+			vars:  map[string]float64{"e": 1},
+			want:  1,
+		},
+		{
+			name:    "undefined variable",
+			input:   "1 + z",
+			wantErr: ErrUndefinedVariable,
+			wantPos: 4,
+		},
+		{
+			name:    "division by zero",
+			input:   "1 / (x - 1)",
+			vars:    map[string]float64{"x": 1},
+			wantErr: ErrDivisionByZero,
+			wantPos: 2,
+		},
+	}
+	e := New()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := e.Eval(tc.input, tc.vars)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				var evalErr *EvalError
+				assert.True(t, errors.As(err, &evalErr))
+				assert.Equal(t, tc.wantPos, evalErr.Pos)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.want, got, 1e-9)
+		})
+	}
+}
+
+func TestExpression_Reuse(t *testing.T) {
+	x, err := New().Parse("a * a")
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		got, err := x.Eval(map[string]float64{"a": float64(i)})
+		assert.NoError(t, err)
+		assert.Equal(t, float64(i*i), got)
+	}
+	assert.Len(t, x.Postfix(), 3)
+}
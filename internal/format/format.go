@@ -0,0 +1,122 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package format implements fmt.Formatter for the collections of the library.
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format writes the size elements of the collection v, enumerated by each, to f:
+//
+//	%v    [1 2 3]
+//	%+v   [0:1 1:2 2:3], the elements are formatted with %+v
+//	%#v   &linkedlist.SinglyLinkedList[int]{1, 2, 3}, the elements are formatted with %#v
+//	%.2v  [1 2 ... (1 more)], the precision is the maximum number of elements
+//	%12v  [1 ... (2 more)], the width is the maximum length of the output, at least one element is written
+//
+// The other verbs and their flags are applied to the elements, e.g. %x or %q.
+// each calls fn with the elements in order until fn returns false.
+func Format[T any](f fmt.State, verb rune, v any, size int, each func(fn func(i int, e T) bool)) {
+	if verb == 'v' && f.Flag('#') {
+		writeGoString(f, v, each)
+		return
+	}
+	element := elementFormat(f, verb)
+	limit, hasLimit := f.Precision()
+	width, hasWidth := f.Width()
+	if !hasLimit {
+		limit = size
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	written := 0
+	each(func(i int, e T) bool {
+		if written >= limit {
+			return false
+		}
+		s := fmt.Sprintf(element, e)
+		if f.Flag('+') {
+			s = strconv.Itoa(i) + ":" + s
+		}
+		if written > 0 {
+			s = " " + s
+		}
+		// the element must leave room for the summary of the others and the closing bracket
+		if hasWidth && written > 0 {
+			length := utf8.RuneCountInString(b.String()) + utf8.RuneCountInString(s) + 1
+			if rest := size - written - 1; rest > 0 {
+				length += len(summary(rest, true))
+			}
+			if length > width {
+				return false
+			}
+		}
+		b.WriteString(s)
+		written++
+		return true
+	})
+	if rest := size - written; rest > 0 {
+		b.WriteString(summary(rest, written > 0))
+	}
+	b.WriteByte(']')
+	_, _ = f.Write([]byte(b.String()))
+}
+
+// summary describes the n elements left out
+func summary(n int, space bool) string {
+	s := fmt.Sprintf("... (%d more)", n)
+	if space {
+		s = " " + s
+	}
+	return s
+}
+
+// elementFormat returns the format of the elements for the verb, with the flags of f but neither width nor precision
+func elementFormat(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// writeGoString writes the Go syntax of the collection v, all its elements are written
+func writeGoString[T any](f fmt.State, v any, each func(fn func(i int, e T) bool)) {
+	var b strings.Builder
+	typ := fmt.Sprintf("%T", v)
+	if strings.HasPrefix(typ, "*") {
+		typ = "&" + typ[1:]
+	}
+	b.WriteString(typ)
+	b.WriteByte('{')
+	each(func(i int, e T) bool {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%#v", e)
+		return true
+	})
+	b.WriteByte('}')
+	_, _ = f.Write([]byte(b.String()))
+}
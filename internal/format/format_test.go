@@ -0,0 +1,76 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// list is a minimal collection formatted by Format
+type list[T any] []T
+
+func (l list[T]) Format(f fmt.State, verb rune) {
+	Format(f, verb, l, len(l), func(fn func(i int, e T) bool) {
+		for i, e := range l {
+			if !fn(i, e) {
+				return
+			}
+		}
+	})
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestFormat(t *testing.T) {
+	ints := list[int]{1, 2, 3, 4, 5}
+	testCases := []struct {
+		name   string
+		format string
+		value  any
+		want   string
+	}{
+		{name: "empty", format: "%v", value: list[int]{}, want: "[]"},
+		{name: "v", format: "%v", value: ints, want: "[1 2 3 4 5]"},
+		{name: "plus", format: "%+v", value: list[point]{{1, 2}, {3, 4}}, want: "[0:{X:1 Y:2} 1:{X:3 Y:4}]"},
+		{name: "sharp", format: "%#v", value: list[point]{{1, 2}}, want: "format.list[github.com/chenmingyong0423/algorithms/internal/format.point]{format.point{X:1, Y:2}}"},
+		{name: "precision", format: "%.2v", value: ints, want: "[1 2 ... (3 more)]"},
+		{name: "zero precision", format: "%.0v", value: ints, want: "[... (5 more)]"},
+		{name: "precision with indices", format: "%+.1v", value: ints, want: "[0:1 ... (4 more)]"},
+		{name: "width", format: "%18v", value: ints, want: "[1 2 ... (3 more)]"},
+		{name: "large width", format: "%80v", value: ints, want: "[1 2 3 4 5]"},
+		{name: "small width", format: "%1v", value: ints, want: "[1 ... (4 more)]"},
+		{name: "element verb", format: "%x", value: list[int]{10, 255}, want: "[a ff]"},
+		{name: "element flags", format: "%q", value: list[string]{"a b", "c"}, want: `["a b" "c"]`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, fmt.Sprintf(tc.format, tc.value))
+		})
+	}
+}
+
+func TestFormat_Huge(t *testing.T) {
+	huge := make(list[int], 1000003)
+	for i := range huge {
+		huge[i] = i + 1
+	}
+	assert.Equal(t, "[1 2 3 ... (1000000 more)]", fmt.Sprintf("%.3v", huge))
+	assert.Equal(t, "[1 2 3 ... (1000000 more)]", fmt.Sprintf("%26v", huge))
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hashing combines the hashes of the elements of the collections of the library.
+package hashing
+
+// The constants of the 64-bit FNV-1a hash
+const (
+	offset64 = 14695981039346656037
+	prime64  = 1099511628211
+)
+
+// Sequence returns a hash of the elements enumerated by each, hashed by hash.
+// The hash depends on the order of the elements and on their number, and it is stable across processes
+// as long as hash is.
+func Sequence[T any](each func(fn func(i int, e T) bool), hash func(e T) uint64) uint64 {
+	h, n := uint64(offset64), uint64(0)
+	each(func(_ int, e T) bool {
+		h = mix(h, hash(e))
+		n++
+		return true
+	})
+	return mix(h, n)
+}
+
+// mix folds x into h, 8 bits at a time like FNV-1a
+func mix(h, x uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= x & 0xff
+		h *= prime64
+		x >>= 8
+	}
+	return h
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequence(t *testing.T) {
+	each := func(elements ...uint64) func(fn func(i int, e uint64) bool) {
+		return func(fn func(i int, e uint64) bool) {
+			for i, e := range elements {
+				if !fn(i, e) {
+					return
+				}
+			}
+		}
+	}
+	identity := func(e uint64) uint64 {
+		return e
+	}
+	// the hash is stable, a change of the algorithm must be deliberate
+	assert.Equal(t, uint64(0xa8c7f832281a39c5), Sequence(each(), identity))
+	assert.Equal(t, Sequence(each(1, 2, 3), identity), Sequence(each(1, 2, 3), identity))
+	assert.NotEqual(t, Sequence(each(1, 2, 3), identity), Sequence(each(3, 2, 1), identity))
+	assert.NotEqual(t, Sequence(each(0), identity), Sequence(each(0, 0), identity))
+	assert.NotEqual(t, Sequence(each(), identity), Sequence(each(0), identity))
+}
@@ -14,44 +14,74 @@
 
 package linkedlist
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var (
+	_ LinkedList[any] = (*ConcurrentLinkedList[any])(nil)
+	_ Observable[any] = (*ConcurrentLinkedList[any])(nil)
+)
 
 type ConcurrentLinkedList[T any] struct {
 	list LinkedList[T]
 	lock *sync.RWMutex
+
+	// notFull is signalled when elements are removed, it is only used by option.OverflowBlock
+	notFull *sync.Cond
+	maxSize int
+	onEvict func(elements []T)
 }
 
 // NewDefaultConcurrentLinkedList returns a new ConcurrentLinkedList with the default SinglyLinkedList.
-func NewDefaultConcurrentLinkedList[T any]() *ConcurrentLinkedList[T] {
-	return &ConcurrentLinkedList[T]{
-		list: NewSinglyLinkedList[T](),
-		lock: &sync.RWMutex{},
-	}
+// The opts configure both the ConcurrentLinkedList and the SinglyLinkedList.
+func NewDefaultConcurrentLinkedList[T any](opts ...option.Option[T]) *ConcurrentLinkedList[T] {
+	return NewConcurrentLinkedList[T](NewSinglyLinkedListWithOptions[T](opts...), opts...)
 }
 
-func NewConcurrentLinkedList[T any](list LinkedList[T]) *ConcurrentLinkedList[T] {
-	return &ConcurrentLinkedList[T]{
-		list: list,
-		lock: &sync.RWMutex{},
+// NewConcurrentLinkedList returns a new ConcurrentLinkedList wrapping the list.
+// The ConcurrentLinkedList only honours option.WithMaxSize together with option.OverflowBlock:
+// the operations adding elements block until there is enough room.
+// The other overflow policies have to be configured on the wrapped list.
+func NewConcurrentLinkedList[T any](list LinkedList[T], opts ...option.Option[T]) *ConcurrentLinkedList[T] {
+	o := option.Apply(opts...)
+	l := &ConcurrentLinkedList[T]{
+		list:    list,
+		lock:    &sync.RWMutex{},
+		onEvict: o.OnEvict,
+	}
+	if o.Overflow == option.OverflowBlock {
+		l.maxSize = o.MaxSize
 	}
+	l.notFull = sync.NewCond(l.lock)
+	return l
 }
 
 func (l *ConcurrentLinkedList[T]) Add(elements ...T) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	l.list.Add(elements...)
+	if l.waitForRoom(elements) {
+		l.list.Add(elements...)
+	}
 }
 
 func (l *ConcurrentLinkedList[T]) Append(elements ...T) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	l.list.Append(elements...)
+	if l.waitForRoom(elements) {
+		l.list.Append(elements...)
+	}
 }
 
 func (l *ConcurrentLinkedList[T]) Prepend(elements ...T) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	l.list.Prepend(elements...)
+	if l.waitForRoom(elements) {
+		l.list.Prepend(elements...)
+	}
 }
 
 func (l *ConcurrentLinkedList[T]) GetFirst() (T, bool) {
@@ -81,25 +111,40 @@ func (l *ConcurrentLinkedList[T]) Set(index int, e T) bool {
 func (l *ConcurrentLinkedList[T]) Insert(index int, elements ...T) bool {
 	l.lock.Lock()
 	defer l.lock.Unlock()
+	if l.isInvalidInsertIndex(index) || !l.waitForRoom(elements) {
+		return false
+	}
 	return l.list.Insert(index, elements...)
 }
 
 func (l *ConcurrentLinkedList[T]) RemoveFirst() (T, bool) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	return l.list.RemoveFirst()
+	t, ok := l.list.RemoveFirst()
+	if ok {
+		l.notFull.Broadcast()
+	}
+	return t, ok
 }
 
 func (l *ConcurrentLinkedList[T]) RemoveLast() (T, bool) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	return l.list.RemoveLast()
+	t, ok := l.list.RemoveLast()
+	if ok {
+		l.notFull.Broadcast()
+	}
+	return t, ok
 }
 
 func (l *ConcurrentLinkedList[T]) Remove(index int) (T, bool) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	return l.list.Remove(index)
+	t, ok := l.list.Remove(index)
+	if ok {
+		l.notFull.Broadcast()
+	}
+	return t, ok
 }
 
 func (l *ConcurrentLinkedList[T]) IsEmpty() bool {
@@ -118,6 +163,7 @@ func (l *ConcurrentLinkedList[T]) Clear() {
 	l.lock.Lock()
 	defer l.lock.Unlock()
 	l.list.Clear()
+	l.notFull.Broadcast()
 }
 
 func (l *ConcurrentLinkedList[T]) Values() []T {
@@ -131,3 +177,148 @@ func (l *ConcurrentLinkedList[T]) Reverse() {
 	defer l.lock.Unlock()
 	l.list.Reverse()
 }
+
+func (l *ConcurrentLinkedList[T]) GetFirstE() (T, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return getFirstE[T](l.list)
+}
+
+func (l *ConcurrentLinkedList[T]) GetLastE() (T, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return getLastE[T](l.list)
+}
+
+func (l *ConcurrentLinkedList[T]) GetE(index int) (T, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return getE[T](l.list, index)
+}
+
+func (l *ConcurrentLinkedList[T]) SetE(index int, e T) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return setE[T](l.list, index, e)
+}
+
+// AddE appends the specified elements to the end of the list.
+// If the elements are rejected because the list is full, errs.ErrFull is returned
+func (l *ConcurrentLinkedList[T]) AddE(elements ...T) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.waitForRoom(elements) {
+		return errs.ErrFull
+	}
+	return addE[T](l.list, elements...)
+}
+
+func (l *ConcurrentLinkedList[T]) InsertE(index int, elements ...T) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.isInvalidInsertIndex(index) {
+		return errs.ErrIndexOutOfRange{Index: index, Size: l.list.Size()}
+	}
+	if !l.waitForRoom(elements) {
+		return errs.ErrFull
+	}
+	return insertE[T](l.list, index, elements...)
+}
+
+func (l *ConcurrentLinkedList[T]) RemoveFirstE() (T, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	t, err := removeFirstE[T](l.list)
+	if err == nil {
+		l.notFull.Broadcast()
+	}
+	return t, err
+}
+
+func (l *ConcurrentLinkedList[T]) RemoveLastE() (T, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	t, err := removeLastE[T](l.list)
+	if err == nil {
+		l.notFull.Broadcast()
+	}
+	return t, err
+}
+
+func (l *ConcurrentLinkedList[T]) RemoveE(index int) (T, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	t, err := removeE[T](l.list, index)
+	if err == nil {
+		l.notFull.Broadcast()
+	}
+	return t, err
+}
+
+// Observe registers the hooks called after the list has been changed
+// and returns a function unregistering them.
+// The hooks are called with the lock held, so they must not call the methods of the list.
+// If the wrapped list is not Observable, the hooks are never called and cancel does nothing.
+func (l *ConcurrentLinkedList[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	observable, ok := l.list.(Observable[T])
+	if !ok {
+		return func() {}
+	}
+	unobserve := observable.Observe(hooks)
+	return func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		unobserve()
+	}
+}
+
+// Subscribe returns a channel receiving the changes of the list in order, and a function to cancel the subscription.
+// The events are queued without limit, so a slow receiver never blocks the list.
+// The channel is closed once the subscription is canceled.
+// The events are delivered by a goroutine which runs until then, so cancel must be called
+// once the events are not needed anymore, otherwise the goroutine leaks.
+// If the wrapped list is not Observable, the channel is closed at once and cancel does nothing.
+func (l *ConcurrentLinkedList[T]) Subscribe() (<-chan Event[T], func()) {
+	if _, ok := l.list.(Observable[T]); !ok {
+		events := make(chan Event[T])
+		close(events)
+		return events, func() {}
+	}
+	f := newFeed[T]()
+	unobserve := l.Observe(f.hooks())
+	var once sync.Once
+	return f.out, func() {
+		once.Do(func() {
+			unobserve()
+			f.close()
+		})
+	}
+}
+
+// isInvalidInsertIndex checks whether the elements can not be inserted at the index, it must be called with the lock held.
+// It is checked before waiting for room so that an invalid index fails at once instead of blocking.
+func (l *ConcurrentLinkedList[T]) isInvalidInsertIndex(index int) bool {
+	size := l.list.Size()
+	// index 0 is valid for an empty list
+	return (index < 0 || index >= size) && (index != 0 || size != 0)
+}
+
+// waitForRoom blocks until the elements fit in the list, it must be called with the write lock held.
+// It returns false if the elements can never fit.
+func (l *ConcurrentLinkedList[T]) waitForRoom(elements []T) bool {
+	if l.maxSize <= 0 {
+		return true
+	}
+	if len(elements) > l.maxSize {
+		if l.onEvict != nil {
+			l.onEvict(elements)
+		}
+		return false
+	}
+	for l.list.Size()+len(elements) > l.maxSize {
+		l.notFull.Wait()
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentLinkedList_OverflowBlock(t *testing.T) {
+	var evicted []int
+	l := NewDefaultConcurrentLinkedList[int](
+		option.WithMaxSize[int](2),
+		option.WithOverflowPolicy[int](option.OverflowBlock),
+		option.WithOnEvict[int](func(elements []int) {
+			evicted = append(evicted, elements...)
+		}),
+	)
+	l.Add(1, 2)
+
+	added := make(chan struct{})
+	go func() {
+		l.Add(3)
+		close(added)
+	}()
+	select {
+	case <-added:
+		t.Fatal("add should block while the list is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first, ok := l.RemoveFirst()
+	assert.True(t, ok)
+	assert.Equal(t, 1, first)
+	<-added
+	assert.Equal(t, []int{2, 3}, l.Values())
+
+	assert.False(t, l.Insert(0, 4, 5, 6))
+	assert.Equal(t, []int{4, 5, 6}, evicted)
+
+	// an invalid index fails at once instead of waiting for room
+	assert.False(t, l.Insert(5, 4))
+	assert.Equal(t, errs.ErrIndexOutOfRange{Index: -1, Size: 2}, l.InsertE(-1, 4))
+	assert.Equal(t, []int{2, 3}, l.Values())
+}
+
+func TestConcurrentLinkedList_Concurrent(t *testing.T) {
+	l := NewDefaultConcurrentLinkedList[int](
+		option.WithMaxSize[int](8),
+		option.WithOverflowPolicy[int](option.OverflowBlock),
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Add(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; {
+				if _, ok := l.RemoveFirst(); ok {
+					j++
+				}
+				assert.LessOrEqual(t, l.Size(), 8)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.True(t, l.IsEmpty())
+}
+
+// newFeedHooks returns the hooks appending the changes to events
+func newFeedHooks(events *[]Event[int]) option.Hooks[int] {
+	return option.Hooks[int]{
+		OnAdd: func(index int, e int) {
+			*events = append(*events, Event[int]{Kind: EventAdd, Index: index, Value: e})
+		},
+		OnRemove: func(index int, e int) {
+			*events = append(*events, Event[int]{Kind: EventRemove, Index: index, Value: e})
+		},
+		OnSet: func(index int, old, e int) {
+			*events = append(*events, Event[int]{Kind: EventSet, Index: index, Value: e, Old: old})
+		},
+		OnClear: func() {
+			*events = append(*events, Event[int]{Kind: EventClear})
+		},
+		OnReverse: func() {
+			*events = append(*events, Event[int]{Kind: EventReverse})
+		},
+	}
+}
+
+func TestConcurrentLinkedList_Subscribe(t *testing.T) {
+	l := NewConcurrentLinkedList[int](NewDoublyLinkedList[int]())
+	events, cancel := l.Subscribe()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.Add(i)
+		}(i)
+	}
+	wg.Wait()
+	l.Set(0, 10)
+	l.Clear()
+
+	var got []EventKind
+	for i := 0; i < 6; i++ {
+		got = append(got, (<-events).Kind)
+	}
+	assert.Equal(t, []EventKind{EventAdd, EventAdd, EventAdd, EventAdd, EventSet, EventClear}, got)
+
+	cancel()
+	cancel()
+	l.Add(1)
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+// plainList hides the Observe method of the list it wraps
+type plainList[T any] struct {
+	LinkedList[T]
+}
+
+func TestConcurrentLinkedList_NotObservable(t *testing.T) {
+	l := NewConcurrentLinkedList[int](plainList[int]{NewDoublyLinkedList[int]()})
+	called := false
+	cancel := l.Observe(option.Hooks[int]{
+		OnAdd: func(index int, e int) {
+			called = true
+		},
+	})
+	events, unsubscribe := l.Subscribe()
+	l.Add(1)
+	cancel()
+	unsubscribe()
+	assert.False(t, called)
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestConcurrentLinkedList_ErrorVariants(t *testing.T) {
+	l := NewDefaultConcurrentLinkedList[int](
+		option.WithMaxSize[int](2),
+		option.WithOverflowPolicy[int](option.OverflowBlock),
+	)
+	_, err := l.RemoveFirstE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = l.GetLastE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	assert.Equal(t, errs.ErrIndexOutOfRange{Index: 1, Size: 0}, l.InsertE(1, 1))
+	assert.Equal(t, errs.ErrFull, l.InsertE(0, 1, 2, 3))
+	assert.Equal(t, errs.ErrFull, l.AddE(1, 2, 3))
+	assert.NoError(t, l.InsertE(0, 1, 2))
+	assert.Equal(t, errs.ErrIndexOutOfRange{Index: 2, Size: 2}, l.SetE(2, 3))
+
+	removed := make(chan struct{})
+	go func() {
+		defer close(removed)
+		v, err := l.RemoveE(0)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v)
+	}()
+	// blocks until the element is removed
+	assert.NoError(t, l.InsertE(0, 3))
+	<-removed
+	assert.Equal(t, []int{3, 2}, l.Values())
+}
+
+func TestConcurrentLinkedList_ConcurrentGet(t *testing.T) {
+	l := NewConcurrentLinkedList[int](NewDoublyLinkedList[int](0, 1, 2, 3, 4, 5, 6, 7))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v, ok := l.Get((i + j) % 8)
+				assert.True(t, ok)
+				assert.Equal(t, (i+j)%8, v)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -14,7 +14,17 @@
 
 package linkedlist
 
-var _ LinkedList[any] = (*DoublyLinkedList[any])(nil)
+import (
+	"sync/atomic"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var (
+	_ LinkedList[any] = (*DoublyLinkedList[any])(nil)
+	_ Observable[any] = (*DoublyLinkedList[any])(nil)
+)
 
 type DoublyNode[T any] struct {
 	val  T
@@ -22,36 +32,53 @@ type DoublyNode[T any] struct {
 	next *DoublyNode[T]
 }
 
+// finger is a cached position of a doubly linked list
+type finger[T any] struct {
+	index int
+	node  *DoublyNode[T]
+}
+
 type DoublyLinkedList[T any] struct {
 	head *DoublyNode[T]
 	tail *DoublyNode[T]
 	size int
+	// finger caches the last accessed node, so that sequential index access is amortized O(1).
+	// It is atomic because Get may be called concurrently under the read lock of ConcurrentLinkedList.
+	finger atomic.Pointer[finger[T]]
+
+	overflow  overflow[T]
+	equal     func(a, b T) bool
+	alloc     option.Allocator[DoublyNode[T]]
+	observers option.Observers[T]
 }
 
 // NewDoublyLinkedList returns a new doubly linked list.
 // If the elements is not empty, add the elements to the list.
 func NewDoublyLinkedList[T any](elements ...T) *DoublyLinkedList[T] {
-	list := &DoublyLinkedList[T]{}
+	list := NewDoublyLinkedListWithOptions[T]()
 	if len(elements) > 0 {
 		list.Add(elements...)
 	}
 	return list
 }
 
+// NewDoublyLinkedListWithOptions returns a new doubly linked list configured by opts.
+// The nodes are allocated by the option.Allocator[DoublyNode[T]] set by option.WithAllocator, if any.
+func NewDoublyLinkedListWithOptions[T any](opts ...option.Option[T]) *DoublyLinkedList[T] {
+	o := option.Apply(opts...)
+	list := &DoublyLinkedList[T]{
+		overflow:  newOverflow(o),
+		equal:     o.Equal,
+		observers: option.NewObservers(o.Hooks),
+		alloc:     option.AllocatorOf[DoublyNode[T]](o),
+	}
+	return list
+}
+
 // Add appends the specified elements to the end of the list.(same as Append)
+// If the list would exceed its max size, the overflow policy is applied.
 func (l *DoublyLinkedList[T]) Add(elements ...T) {
-	if len(elements) > 0 {
-		for _, e := range elements {
-			node := &DoublyNode[T]{val: e, prev: l.tail}
-			if l.IsEmpty() {
-				l.head, l.tail = node, node
-			} else {
-				l.tail.next = node
-				l.tail = node
-			}
-			l.size++
-		}
-	}
+	_ = l.AddE(elements...)
 }
 
 // Append appends the specified elements to the end of the list.(same as Add)
@@ -60,16 +87,10 @@ func (l *DoublyLinkedList[T]) Append(elements ...T) {
 }
 
 // Prepend prepends the specified elements to the beginning of the list.
+// If the list would exceed its max size, the overflow policy is applied.
 func (l *DoublyLinkedList[T]) Prepend(elements ...T) {
-	for i := len(elements) - 1; i >= 0; i-- {
-		node := &DoublyNode[T]{val: elements[i], next: l.head}
-		if l.size == 0 {
-			l.tail = node
-		} else {
-			l.head.prev = node
-		}
-		l.head = node
-		l.size++
+	if elements, _, ok := l.overflow.makeRoom(l, elements); ok {
+		l.prepend(elements...)
 	}
 }
 
@@ -97,16 +118,7 @@ func (l *DoublyLinkedList[T]) Get(index int) (t T, b bool) {
 	if l.isInvalidIndex(index) {
 		return
 	}
-	if index == 0 {
-		return l.head.val, true
-	}
-	if index == l.size-1 {
-		return l.tail.val, true
-	}
-	node := l.head
-	for i := 0; i < index; i, node = i+1, node.next {
-	}
-	return node.val, true
+	return l.node(index).val, true
 }
 
 // Set sets the element at the specified position in the list.
@@ -115,41 +127,29 @@ func (l *DoublyLinkedList[T]) Set(index int, e T) bool {
 	if l.isInvalidIndex(index) {
 		return false
 	}
-	node := l.head
-	for i := 0; i < index; i, node = i+1, node.next {
-	}
+	node := l.node(index)
+	old := node.val
 	node.val = e
+	l.observers.NotifySet(index, old, e)
 	return true
 }
 
 // Insert inserts the specified elements at the specified position in the list.
+// If the list would exceed its max size, the overflow policy is applied,
+// false is returned if the elements are rejected.
 func (l *DoublyLinkedList[T]) Insert(index int, elements ...T) bool {
-	if l.isInvalidIndex(index) {
-		if index == 0 {
-			l.Add(elements...)
-			return true
-		}
+	if l.isInvalidIndex(index) && (index != 0 || !l.IsEmpty()) {
 		return false
 	}
-	if index == 0 {
-		l.Prepend(elements...)
-		return true
-	}
-	if index == l.Size()-1 {
-		l.Append(elements...)
-		return true
-	}
-	prev := l.head
-	for i := 0; i < index-1; i, prev = i+1, prev.next {
+	// inserting at the last position appends the elements, except at 0 which always prepends
+	if index != 0 && index == l.Size()-1 {
+		index = l.Size()
 	}
-	oldNext := prev.next
-	for _, e := range elements {
-		node := &DoublyNode[T]{val: e, prev: prev}
-		prev.next = node
-		prev = node
-		l.size++
+	elements, evicted, ok := l.overflow.makeRoom(l, elements)
+	if !ok {
+		return false
 	}
-	prev.next = oldNext
+	l.insert(min(max(index-evicted, 0), l.Size()), elements...)
 	return true
 }
 
@@ -159,15 +159,7 @@ func (l *DoublyLinkedList[T]) RemoveFirst() (t T, b bool) {
 	if l.IsEmpty() {
 		return
 	}
-	head := l.head
-	l.head = head.next
-	l.size--
-	if l.IsEmpty() {
-		l.tail = nil
-	} else {
-		l.head.prev = nil
-	}
-	return head.val, true
+	return l.removeFirst(), true
 }
 
 // RemoveLast removes the last element from the list.
@@ -176,14 +168,7 @@ func (l *DoublyLinkedList[T]) RemoveLast() (t T, b bool) {
 	if l.IsEmpty() {
 		return
 	}
-	if l.Size() == 1 {
-		return l.RemoveFirst()
-	}
-	tail := l.tail
-	l.tail = tail.prev
-	l.tail.next = nil
-	l.size--
-	return tail.val, true
+	return l.removeLast(), true
 }
 
 // Remove removes the element at the specified position in the list.
@@ -193,19 +178,19 @@ func (l *DoublyLinkedList[T]) Remove(index int) (t T, b bool) {
 		return
 	}
 	if index == 0 {
-		return l.RemoveFirst()
+		return l.removeFirst(), true
 	}
 	if index == l.Size()-1 {
-		return l.RemoveLast()
-	}
-	prev := l.head
-	for i := 0; i < index-1; i, prev = i+1, prev.next {
+		return l.removeLast(), true
 	}
-	dst := prev.next
-	prev.next = dst.next
-	dst.next.prev = prev
+	dst := l.node(index)
+	dst.prev.next = dst.next
+	dst.next.prev = dst.prev
 	l.size--
-	t, dst = dst.val, nil
+	l.unlinkFinger(dst, index)
+	t = dst.val
+	l.freeNode(dst)
+	l.observers.NotifyRemove(index, t)
 	return t, true
 }
 
@@ -221,9 +206,18 @@ func (l *DoublyLinkedList[T]) Size() int {
 
 // Clear removes all the elements from the list
 func (l *DoublyLinkedList[T]) Clear() {
+	if l.alloc != nil {
+		for node := l.head; node != nil; {
+			next := node.next
+			l.freeNode(node)
+			node = next
+		}
+	}
 	l.head = nil
 	l.tail = nil
 	l.size = 0
+	l.finger.Store(nil)
+	l.observers.NotifyClear()
 }
 
 // Values returns a slice containing all the elements in this list.
@@ -237,6 +231,17 @@ func (l *DoublyLinkedList[T]) Values() []T {
 	return elements
 }
 
+// each calls fn for the elements in order, until fn returns false
+func (l *DoublyLinkedList[T]) each(fn func(index int, e T) bool) {
+	index := 0
+	for node := l.head; node != nil; node = node.next {
+		if !fn(index, node.val) {
+			return
+		}
+		index++
+	}
+}
+
 // isInvalidIndex checks whether the index is invalid
 func (l *DoublyLinkedList[T]) isInvalidIndex(index int) bool {
 	return index < 0 || index > l.Size()-1
@@ -246,6 +251,7 @@ func (l *DoublyLinkedList[T]) isInvalidIndex(index int) bool {
 func (l *DoublyLinkedList[T]) Reverse() {
 	var prev *DoublyNode[T]
 	cur := l.head
+	l.tail = cur
 	for cur != nil {
 		next := cur.next
 		cur.next = prev
@@ -254,4 +260,234 @@ func (l *DoublyLinkedList[T]) Reverse() {
 		cur = next
 	}
 	l.head = prev
+	if f := l.finger.Load(); f != nil {
+		l.finger.Store(&finger[T]{index: l.size - 1 - f.index, node: f.node})
+	}
+	l.observers.NotifyReverse()
+}
+
+// GetFirstE returns the first element in the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *DoublyLinkedList[T]) GetFirstE() (T, error) {
+	return getFirstE[T](l)
+}
+
+// GetLastE returns the last element in the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *DoublyLinkedList[T]) GetLastE() (T, error) {
+	return getLastE[T](l)
+}
+
+// GetE returns the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *DoublyLinkedList[T]) GetE(index int) (T, error) {
+	return getE[T](l, index)
+}
+
+// SetE sets the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *DoublyLinkedList[T]) SetE(index int, e T) error {
+	return setE[T](l, index, e)
+}
+
+// AddE appends the specified elements to the end of the list.
+// If the elements are rejected because the list is full, errs.ErrFull is returned
+func (l *DoublyLinkedList[T]) AddE(elements ...T) error {
+	elements, _, ok := l.overflow.makeRoom(l, elements)
+	if !ok {
+		return errs.ErrFull
+	}
+	l.add(elements...)
+	return nil
+}
+
+// InsertE inserts the specified elements at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned,
+// if the elements are rejected because the list is full, errs.ErrFull is returned
+func (l *DoublyLinkedList[T]) InsertE(index int, elements ...T) error {
+	return insertE[T](l, index, elements...)
+}
+
+// RemoveFirstE removes the first element from the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *DoublyLinkedList[T]) RemoveFirstE() (T, error) {
+	return removeFirstE[T](l)
+}
+
+// RemoveLastE removes the last element from the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *DoublyLinkedList[T]) RemoveLastE() (T, error) {
+	return removeLastE[T](l)
+}
+
+// RemoveE removes the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *DoublyLinkedList[T]) RemoveE(index int) (T, error) {
+	return removeE[T](l, index)
+}
+
+// IndexOf returns the index of the first occurrence of the element in the list, or -1 if it is not found.
+// Elements are compared with the function set by option.WithEqual, or with == if there is none,
+// in which case the elements which are not comparable, e.g. slices, are never equal.
+func (l *DoublyLinkedList[T]) IndexOf(e T) int {
+	equal := l.equal
+	if equal == nil {
+		equal = defaultEqual[T]
+	}
+	for i, node := 0, l.head; node != nil; i, node = i+1, node.next {
+		if equal(node.val, e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains checks whether the list contains the element.
+func (l *DoublyLinkedList[T]) Contains(e T) bool {
+	return l.IndexOf(e) != -1
+}
+
+// Observe registers the hooks called after the list has been changed
+// and returns a function unregistering them.
+func (l *DoublyLinkedList[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	return l.observers.Observe(hooks)
+}
+
+// node returns the node at the specified position, the index must be valid.
+// It walks from the closest of the head, the tail and the finger, and moves the finger to the node.
+func (l *DoublyLinkedList[T]) node(index int) *DoublyNode[T] {
+	node, pos := l.head, 0
+	if l.size-1-index < index {
+		node, pos = l.tail, l.size-1
+	}
+	f := l.finger.Load()
+	if f != nil && abs(index-f.index) < abs(index-pos) {
+		node, pos = f.node, f.index
+	}
+	for ; pos < index; pos++ {
+		node = node.next
+	}
+	for ; pos > index; pos-- {
+		node = node.prev
+	}
+	if f == nil || f.node != node {
+		l.finger.Store(&finger[T]{index: index, node: node})
+	}
+	return node
+}
+
+// shiftFinger moves the index of the finger by delta if it is not less than from
+func (l *DoublyLinkedList[T]) shiftFinger(from, delta int) {
+	if f := l.finger.Load(); f != nil && f.index >= from {
+		l.finger.Store(&finger[T]{index: f.index + delta, node: f.node})
+	}
+}
+
+// unlinkFinger updates the finger after the node at the index has been removed
+func (l *DoublyLinkedList[T]) unlinkFinger(node *DoublyNode[T], index int) {
+	if f := l.finger.Load(); f != nil && f.node == node {
+		l.finger.Store(nil)
+		return
+	}
+	l.shiftFinger(index+1, -1)
+}
+
+func (l *DoublyLinkedList[T]) newNode(e T, prev, next *DoublyNode[T]) *DoublyNode[T] {
+	if l.alloc == nil {
+		return &DoublyNode[T]{val: e, prev: prev, next: next}
+	}
+	node := l.alloc.Alloc()
+	node.val, node.prev, node.next = e, prev, next
+	return node
+}
+
+func (l *DoublyLinkedList[T]) freeNode(node *DoublyNode[T]) {
+	if l.alloc != nil {
+		l.alloc.Free(node)
+	}
+}
+
+func (l *DoublyLinkedList[T]) add(elements ...T) {
+	for _, e := range elements {
+		node := l.newNode(e, l.tail, nil)
+		if l.IsEmpty() {
+			l.head, l.tail = node, node
+		} else {
+			l.tail.next = node
+			l.tail = node
+		}
+		l.size++
+		l.observers.NotifyAdd(l.size-1, e)
+	}
+}
+
+func (l *DoublyLinkedList[T]) prepend(elements ...T) {
+	for i := len(elements) - 1; i >= 0; i-- {
+		node := l.newNode(elements[i], nil, l.head)
+		if l.size == 0 {
+			l.tail = node
+		} else {
+			l.head.prev = node
+		}
+		l.head = node
+		l.size++
+		l.shiftFinger(0, 1)
+		l.observers.NotifyAdd(0, elements[i])
+	}
+}
+
+// insert inserts the elements before the index, the index must be in [0, size]
+func (l *DoublyLinkedList[T]) insert(index int, elements ...T) {
+	if index == l.Size() {
+		l.add(elements...)
+		return
+	}
+	if index == 0 {
+		l.prepend(elements...)
+		return
+	}
+	next := l.node(index)
+	prev := next.prev
+	for i, e := range elements {
+		node := l.newNode(e, prev, next)
+		prev.next = node
+		next.prev = node
+		prev = node
+		l.size++
+		l.shiftFinger(index+i, 1)
+		l.observers.NotifyAdd(index+i, e)
+	}
+}
+
+// removeFirst removes the first element, the list must not be empty
+func (l *DoublyLinkedList[T]) removeFirst() T {
+	head := l.head
+	l.head = head.next
+	l.size--
+	l.unlinkFinger(head, 0)
+	if l.IsEmpty() {
+		l.tail = nil
+	} else {
+		l.head.prev = nil
+	}
+	t := head.val
+	l.freeNode(head)
+	l.observers.NotifyRemove(0, t)
+	return t
+}
+
+// removeLast removes the last element, the list must not be empty
+func (l *DoublyLinkedList[T]) removeLast() T {
+	if l.Size() == 1 {
+		return l.removeFirst()
+	}
+	tail := l.tail
+	l.tail = tail.prev
+	l.tail.next = nil
+	l.size--
+	l.unlinkFinger(tail, l.size)
+	t := tail.val
+	l.freeNode(tail)
+	l.observers.NotifyRemove(l.size, t)
+	return t
 }
@@ -15,8 +15,11 @@
 package linkedlist
 
 import (
+	"math/rand"
 	"testing"
 
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -339,6 +342,14 @@ func TestDoublyLinkedList_Insert(t *testing.T) {
 			elements: []int{4, 5},
 			wantBool: false,
 		},
+		{
+			name:             "insert one element at the beginning of single-element list",
+			list:             NewDoublyLinkedList[int](1),
+			index:            0,
+			elements:         []int{9},
+			wantBool:         true,
+			wantListElements: []int{9, 1},
+		},
 		{
 			name:             "insert one element at the beginning of empty list",
 			list:             NewDoublyLinkedList[int](),
@@ -658,6 +669,476 @@ func TestDoublyLinkedList_Reverse(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.list.Reverse()
 			assert.Equal(t, tc.wantListElements, tc.list.Values())
+			if len(tc.wantListElements) > 0 {
+				last, _ := tc.list.GetLast()
+				assert.Equal(t, tc.wantListElements[len(tc.wantListElements)-1], last)
+			}
+		})
+	}
+}
+
+func TestNewDoublyLinkedListWithOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []option.Option[int]
+		elements []int
+
+		wantListElements []int
+		wantAdded        []int
+		wantRemoved      []int
+	}{
+		{
+			name:             "no options",
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name:             "max size",
+			opts:             []option.Option[int]{option.WithMaxSize[int](2)},
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 2},
+		},
+		{
+			name:             "allocator",
+			opts:             []option.Option[int]{option.WithAllocator[int](option.NewPoolAllocator[DoublyNode[int]]())},
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 3},
+			wantRemoved:      []int{2},
+		},
+		{
+			name:             "allocator of another node type",
+			opts:             []option.Option[int]{option.WithAllocator[int](option.NewPoolAllocator[SinglyNode[int]]())},
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 3},
+			wantRemoved:      []int{2},
+		},
+		{
+			name:             "hooks",
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 3},
+			wantAdded:        []int{1, 2, 3},
+			wantRemoved:      []int{2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var added, removed []int
+			hooks := option.Hooks[int]{
+				OnAdd: func(index int, e int) {
+					added = append(added, e)
+				},
+				OnRemove: func(index int, e int) {
+					assert.Equal(t, 1, index)
+					removed = append(removed, e)
+				},
+			}
+			opts := tc.opts
+			if tc.wantAdded != nil {
+				opts = append(opts, option.WithHooks[int](hooks))
+			}
+			list := NewDoublyLinkedListWithOptions[int](opts...)
+			for _, e := range tc.elements {
+				list.Add(e)
+			}
+			if tc.wantRemoved != nil {
+				list.Remove(1)
+			}
+			assert.Equal(t, tc.wantListElements, list.Values())
+			assert.Equal(t, tc.wantAdded, added)
+			if tc.wantAdded != nil {
+				assert.Equal(t, tc.wantRemoved, removed)
+			}
+		})
+	}
+}
+
+func TestDoublyLinkedList_IndexOf(t *testing.T) {
+	testCases := []struct {
+		name    string
+		list    *DoublyLinkedList[int]
+		element int
+
+		want int
+	}{
+		{
+			name:    "empty list",
+			list:    NewDoublyLinkedList[int](),
+			element: 1,
+			want:    -1,
+		},
+		{
+			name:    "element exists",
+			list:    NewDoublyLinkedList[int](1, 2, 3, 2),
+			element: 2,
+			want:    1,
+		},
+		{
+			name:    "element does not exist",
+			list:    NewDoublyLinkedList[int](1, 2, 3),
+			element: 4,
+			want:    -1,
+		},
+		{
+			name: "custom equal",
+			list: func() *DoublyLinkedList[int] {
+				l := NewDoublyLinkedListWithOptions[int](option.WithEqual[int](func(a, b int) bool {
+					return a%10 == b%10
+				}))
+				l.Add(1, 12, 3)
+				return l
+			}(),
+			element: 2,
+			want:    1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.list.IndexOf(tc.element))
+			assert.Equal(t, tc.want != -1, tc.list.Contains(tc.element))
 		})
 	}
 }
+
+func TestDoublyLinkedList_Overflow(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy option.OverflowPolicy
+		op     func(l *DoublyLinkedList[int]) bool
+
+		wantBool         bool
+		wantListElements []int
+		wantEvicted      []int
+	}{
+		{
+			name:   "reject add",
+			policy: option.OverflowReject,
+			op: func(l *DoublyLinkedList[int]) bool {
+				l.Add(4)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{1, 2, 3},
+			wantEvicted:      []int{4},
+		},
+		{
+			name:   "reject insert",
+			policy: option.OverflowReject,
+			op: func(l *DoublyLinkedList[int]) bool {
+				return l.Insert(1, 4)
+			},
+			wantBool:         false,
+			wantListElements: []int{1, 2, 3},
+			wantEvicted:      []int{4},
+		},
+		{
+			name:   "block is treated as reject",
+			policy: option.OverflowBlock,
+			op: func(l *DoublyLinkedList[int]) bool {
+				return l.Insert(0, 4)
+			},
+			wantBool:         false,
+			wantListElements: []int{1, 2, 3},
+			wantEvicted:      []int{4},
+		},
+		{
+			name:   "evict oldest on add",
+			policy: option.OverflowEvictOldest,
+			op: func(l *DoublyLinkedList[int]) bool {
+				l.Add(4, 5)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{3, 4, 5},
+			wantEvicted:      []int{1, 2},
+		},
+		{
+			name:   "evict oldest on prepend",
+			policy: option.OverflowEvictOldest,
+			op: func(l *DoublyLinkedList[int]) bool {
+				l.Prepend(0)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{0, 2, 3},
+			wantEvicted:      []int{1},
+		},
+		{
+			name:   "evict oldest on insert",
+			policy: option.OverflowEvictOldest,
+			op: func(l *DoublyLinkedList[int]) bool {
+				return l.Insert(2, 4)
+			},
+			wantBool:         true,
+			wantListElements: []int{2, 3, 4},
+			wantEvicted:      []int{1},
+		},
+		{
+			name:   "evict oldest with more elements than max size",
+			policy: option.OverflowEvictOldest,
+			op: func(l *DoublyLinkedList[int]) bool {
+				l.Add(4, 5, 6, 7)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{5, 6, 7},
+			wantEvicted:      []int{1, 2, 3, 4},
+		},
+		{
+			name:   "evict newest on add",
+			policy: option.OverflowEvictNewest,
+			op: func(l *DoublyLinkedList[int]) bool {
+				l.Add(4)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{1, 2, 4},
+			wantEvicted:      []int{3},
+		},
+		{
+			name:   "evict newest on insert",
+			policy: option.OverflowEvictNewest,
+			op: func(l *DoublyLinkedList[int]) bool {
+				return l.Insert(1, 4, 5)
+			},
+			wantBool:         true,
+			wantListElements: []int{1, 4, 5},
+			wantEvicted:      []int{3, 2},
+		},
+		{
+			name:   "evict newest with more elements than max size",
+			policy: option.OverflowEvictNewest,
+			op: func(l *DoublyLinkedList[int]) bool {
+				l.Prepend(4, 5, 6, 7)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{4, 5, 6},
+			wantEvicted:      []int{3, 2, 1, 7},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []int
+			l := NewDoublyLinkedListWithOptions[int](
+				option.WithMaxSize[int](3),
+				option.WithOverflowPolicy[int](tc.policy),
+				option.WithOnEvict[int](func(elements []int) {
+					evicted = append(evicted, elements...)
+				}),
+			)
+			l.Add(1, 2, 3)
+			assert.Equal(t, tc.wantBool, tc.op(l))
+			assert.Equal(t, tc.wantListElements, l.Values())
+			assert.Equal(t, tc.wantEvicted, evicted)
+		})
+	}
+}
+
+func TestDoublyLinkedList_Observe(t *testing.T) {
+	var events []Event[int]
+	l := NewDoublyLinkedList[int](1, 2, 3)
+	cancel := l.Observe(newFeedHooks(&events))
+
+	l.Add(4)
+	l.Insert(1, 5)
+	l.Set(0, 6)
+	l.Remove(2)
+	l.Reverse()
+	l.Clear()
+	cancel()
+	l.Add(7)
+
+	assert.Equal(t, []Event[int]{
+		{Kind: EventAdd, Index: 3, Value: 4},
+		{Kind: EventAdd, Index: 1, Value: 5},
+		{Kind: EventSet, Index: 0, Value: 6, Old: 1},
+		{Kind: EventRemove, Index: 2, Value: 2},
+		{Kind: EventReverse},
+		{Kind: EventClear},
+	}, events)
+}
+
+func TestDoublyLinkedList_ErrorVariants(t *testing.T) {
+	empty := NewDoublyLinkedListWithOptions[int]()
+	_, err := empty.GetFirstE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.GetLastE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.RemoveFirstE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.RemoveLastE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.GetE(0)
+	assert.Equal(t, errs.ErrIndexOutOfRange{Index: 0, Size: 0}, err)
+	assert.NoError(t, empty.InsertE(0, 1, 2, 3))
+
+	testCases := []struct {
+		name string
+		op   func(l *DoublyLinkedList[int]) error
+
+		wantErr          error
+		wantListElements []int
+	}{
+		{
+			name: "get",
+			op: func(l *DoublyLinkedList[int]) error {
+				_, err := l.GetE(1)
+				return err
+			},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "get with invalid index",
+			op: func(l *DoublyLinkedList[int]) error {
+				_, err := l.GetE(3)
+				return err
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: 3, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "set with invalid index",
+			op: func(l *DoublyLinkedList[int]) error {
+				return l.SetE(-1, 0)
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: -1, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "insert with invalid index",
+			op: func(l *DoublyLinkedList[int]) error {
+				return l.InsertE(4, 0)
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: 4, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "add into full list",
+			op: func(l *DoublyLinkedList[int]) error {
+				return l.AddE(0, 0)
+			},
+			wantErr:          errs.ErrFull,
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "insert into full list",
+			op: func(l *DoublyLinkedList[int]) error {
+				return l.InsertE(0, 0, 0)
+			},
+			wantErr:          errs.ErrFull,
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "remove",
+			op: func(l *DoublyLinkedList[int]) error {
+				_, err := l.RemoveE(1)
+				return err
+			},
+			wantListElements: []int{1, 3},
+		},
+		{
+			name: "remove with invalid index",
+			op: func(l *DoublyLinkedList[int]) error {
+				_, err := l.RemoveE(3)
+				return err
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: 3, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewDoublyLinkedListWithOptions[int](option.WithMaxSize[int](4))
+			l.Add(1, 2, 3)
+			err := tc.op(l)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantListElements, l.Values())
+		})
+	}
+}
+
+func TestDoublyLinkedList_Finger(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	l := NewDoublyLinkedList[int]()
+	var want []int
+	for i := 0; i < 5000; i++ {
+		switch op := r.Intn(7); {
+		case op == 0 || len(want) == 0:
+			e := r.Int()
+			l.Prepend(e)
+			want = append([]int{e}, want...)
+		case op == 1:
+			e := r.Int()
+			l.Add(e)
+			want = append(want, e)
+		case op == 2:
+			idx, e := r.Intn(len(want)), r.Int()
+			l.Insert(idx, e)
+			if idx == len(want)-1 {
+				idx++
+			}
+			want = append(want[:idx], append([]int{e}, want[idx:]...)...)
+		case op == 3:
+			idx := r.Intn(len(want))
+			got, _ := l.Remove(idx)
+			assert.Equal(t, want[idx], got)
+			want = append(want[:idx], want[idx+1:]...)
+		case op == 4:
+			idx, e := r.Intn(len(want)), r.Int()
+			l.Set(idx, e)
+			want[idx] = e
+		case op == 5 && r.Intn(10) == 0:
+			l.Reverse()
+			for i, j := 0, len(want)-1; i < j; i, j = i+1, j-1 {
+				want[i], want[j] = want[j], want[i]
+			}
+		default:
+			idx := r.Intn(len(want))
+			got, _ := l.Get(idx)
+			assert.Equal(t, want[idx], got)
+		}
+	}
+	assert.Equal(t, want, l.Values())
+	for i := range want {
+		got, _ := l.Get(i)
+		assert.Equal(t, want[i], got)
+	}
+}
+
+func BenchmarkDoublyLinkedList_Get(b *testing.B) {
+	const size = 10000
+	elements := make([]int, size)
+	singly := NewSinglyLinkedList[int](elements...)
+	doubly := NewDoublyLinkedList[int](elements...)
+	b.Run("singly sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			singly.Get(i % size)
+		}
+	})
+	b.Run("doubly sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			doubly.Get(i % size)
+		}
+	})
+	b.Run("singly backward", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			singly.Get(size - 1 - i%size)
+		}
+	})
+	b.Run("doubly backward", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			doubly.Get(size - 1 - i%size)
+		}
+	})
+	r := rand.New(rand.NewSource(1))
+	b.Run("singly random", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			singly.Get(r.Intn(size))
+		}
+	})
+	b.Run("doubly random", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			doubly.Get(r.Intn(size))
+		}
+	})
+}
@@ -0,0 +1,280 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"sync"
+
+	"github.com/chenmingyong0423/algorithms/internal/hashing"
+)
+
+// cloner is implemented by the lists of the package copying themselves with their configuration
+type cloner[T any] interface {
+	clone(fn func(e T) T) LinkedList[T]
+}
+
+// equaler is implemented by the lists of the package comparing their elements with the function set by option.WithEqual
+type equaler[T any] interface {
+	equalFunc() func(a, b T) bool
+}
+
+// Compare compares the lists lexicographically with cmp, which returns a negative number if a < b,
+// zero if a == b and a positive number if a > b, e.g. cmp.Compare.
+// The first pair of different elements decides, otherwise the shorter list is the lesser.
+func Compare[T any](a, b ReadOnlyList[T], cmp func(a, b T) int) int {
+	values := b.Values()
+	result := 0
+	elements(a)(func(i int, e T) bool {
+		if i == len(values) {
+			result = 1
+			return false
+		}
+		result = cmp(e, values[i])
+		return result == 0
+	})
+	if result == 0 && a.Size() < len(values) {
+		result = -1
+	}
+	return result
+}
+
+// elements returns a function enumerating the elements of the list in order,
+// without copying them for the lists of the package
+func elements[T any](l ReadOnlyList[T]) func(fn func(index int, e T) bool) {
+	if list, ok := l.(iterable[T]); ok {
+		return list.each
+	}
+	return func(fn func(index int, e T) bool) {
+		for i, e := range l.Values() {
+			if !fn(i, e) {
+				return
+			}
+		}
+	}
+}
+
+// equal checks whether the elements enumerated by each are the elements of other, compared with eq
+func equal[T any](size int, each func(fn func(index int, e T) bool), other ReadOnlyList[T], eq func(a, b T) bool) bool {
+	values := other.Values()
+	if size != len(values) {
+		return false
+	}
+	result := true
+	each(func(i int, e T) bool {
+		result = eq(e, values[i])
+		return result
+	})
+	return result
+}
+
+// identity is the clone function of a shallow copy
+func identity[T any](e T) T {
+	return e
+}
+
+// Equal checks whether the list and other have the same elements in the same order, compared with eq.
+// If eq is nil, the function set by option.WithEqual is used, or == if there is none.
+func (l *SinglyLinkedList[T]) Equal(other ReadOnlyList[T], eq func(a, b T) bool) bool {
+	if eq == nil {
+		eq = l.equalFunc()
+	}
+	return equal(l.size, l.each, other, eq)
+}
+
+// Clone returns a copy of the list with the same configuration but without the observers
+func (l *SinglyLinkedList[T]) Clone() *SinglyLinkedList[T] {
+	return l.DeepClone(identity[T])
+}
+
+// DeepClone returns a copy of the list with the same configuration but without the observers,
+// the elements are copied by clone
+func (l *SinglyLinkedList[T]) DeepClone(clone func(e T) T) *SinglyLinkedList[T] {
+	c := &SinglyLinkedList[T]{
+		overflow: l.overflow,
+		equal:    l.equal,
+		alloc:    l.alloc,
+	}
+	for node := l.head; node != nil; node = node.next {
+		c.add(clone(node.val))
+	}
+	return c
+}
+
+// Hash returns a hash of the elements in order, hashed by hash.
+// Lists with equal elements have the same hash, which is stable across processes as long as hash is.
+func (l *SinglyLinkedList[T]) Hash(hash func(e T) uint64) uint64 {
+	return hashing.Sequence(l.each, hash)
+}
+
+func (l *SinglyLinkedList[T]) clone(fn func(e T) T) LinkedList[T] {
+	return l.DeepClone(fn)
+}
+
+func (l *SinglyLinkedList[T]) equalFunc() func(a, b T) bool {
+	if l.equal == nil {
+		return defaultEqual[T]
+	}
+	return l.equal
+}
+
+// Equal checks whether the list and other have the same elements in the same order, compared with eq.
+// If eq is nil, the function set by option.WithEqual is used, or == if there is none.
+func (l *DoublyLinkedList[T]) Equal(other ReadOnlyList[T], eq func(a, b T) bool) bool {
+	if eq == nil {
+		eq = l.equalFunc()
+	}
+	return equal(l.size, l.each, other, eq)
+}
+
+// Clone returns a copy of the list with the same configuration but without the observers
+func (l *DoublyLinkedList[T]) Clone() *DoublyLinkedList[T] {
+	return l.DeepClone(identity[T])
+}
+
+// DeepClone returns a copy of the list with the same configuration but without the observers,
+// the elements are copied by clone
+func (l *DoublyLinkedList[T]) DeepClone(clone func(e T) T) *DoublyLinkedList[T] {
+	c := &DoublyLinkedList[T]{
+		overflow: l.overflow,
+		equal:    l.equal,
+		alloc:    l.alloc,
+	}
+	for node := l.head; node != nil; node = node.next {
+		c.add(clone(node.val))
+	}
+	return c
+}
+
+// Hash returns a hash of the elements in order, see SinglyLinkedList.Hash
+func (l *DoublyLinkedList[T]) Hash(hash func(e T) uint64) uint64 {
+	return hashing.Sequence(l.each, hash)
+}
+
+func (l *DoublyLinkedList[T]) clone(fn func(e T) T) LinkedList[T] {
+	return l.DeepClone(fn)
+}
+
+func (l *DoublyLinkedList[T]) equalFunc() func(a, b T) bool {
+	if l.equal == nil {
+		return defaultEqual[T]
+	}
+	return l.equal
+}
+
+func (l *TreeList[T]) clone(fn func(e T) T) LinkedList[T] {
+	c := &TreeList[T]{
+		overflow: l.overflow,
+		equal:    l.equal,
+	}
+	elements := make([]T, 0, l.Size())
+	l.each(func(_ int, e T) bool {
+		elements = append(elements, fn(e))
+		return true
+	})
+	c.insert(0, elements...)
+	return c
+}
+
+func (l *TreeList[T]) equalFunc() func(a, b T) bool {
+	if l.equal == nil {
+		return defaultEqual[T]
+	}
+	return l.equal
+}
+
+// Equal checks whether the list and other have the same elements in the same order, compared with eq.
+// If eq is nil, the function set by option.WithEqual on the wrapped list is used, or == if there is none.
+// The elements of the list are read under the read lock, those of other are read before.
+func (l *ConcurrentLinkedList[T]) Equal(other ReadOnlyList[T], eq func(a, b T) bool) bool {
+	if other == ReadOnlyList[T](l) {
+		return true
+	}
+	if eq == nil {
+		eq = defaultEqual[T]
+		if list, ok := l.list.(equaler[T]); ok {
+			eq = list.equalFunc()
+		}
+	}
+	// other is read first so that the locks of two concurrent lists are never held together
+	values := other.Values()
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return equal(l.list.Size(), elements[T](l.list), valuesList[T](values), eq)
+}
+
+// Clone returns a copy of the list and of the wrapped list, see DeepClone
+func (l *ConcurrentLinkedList[T]) Clone() *ConcurrentLinkedList[T] {
+	return l.DeepClone(identity[T])
+}
+
+// DeepClone returns a copy of the list and of the wrapped list, the elements are copied by clone.
+// The lists of the package are copied with their configuration but without the observers,
+// the other lists are copied into a SinglyLinkedList.
+func (l *ConcurrentLinkedList[T]) DeepClone(clone func(e T) T) *ConcurrentLinkedList[T] {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	var list LinkedList[T]
+	if c, ok := l.list.(cloner[T]); ok {
+		list = c.clone(clone)
+	} else {
+		singly := NewSinglyLinkedList[T]()
+		elements[T](l.list)(func(_ int, e T) bool {
+			singly.add(clone(e))
+			return true
+		})
+		list = singly
+	}
+	c := &ConcurrentLinkedList[T]{
+		list:    list,
+		lock:    &sync.RWMutex{},
+		maxSize: l.maxSize,
+		onEvict: l.onEvict,
+	}
+	c.notFull = sync.NewCond(c.lock)
+	return c
+}
+
+// Hash returns a hash of the elements in order under the read lock, see SinglyLinkedList.Hash
+func (l *ConcurrentLinkedList[T]) Hash(hash func(e T) uint64) uint64 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return hashing.Sequence(elements[T](l.list), hash)
+}
+
+func (l *ConcurrentLinkedList[T]) clone(fn func(e T) T) LinkedList[T] {
+	return l.DeepClone(fn)
+}
+
+// valuesList is a ReadOnlyList over a slice
+type valuesList[T any] []T
+
+func (v valuesList[T]) Get(index int) (t T, b bool) {
+	if index < 0 || index >= len(v) {
+		return
+	}
+	return v[index], true
+}
+
+func (v valuesList[T]) IsEmpty() bool {
+	return len(v) == 0
+}
+
+func (v valuesList[T]) Size() int {
+	return len(v)
+}
+
+func (v valuesList[T]) Values() []T {
+	return v
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"cmp"
+	"hash/maphash"
+	"strings"
+	"testing"
+
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func hashInt(e int) uint64 {
+	return uint64(e)
+}
+
+func TestLinkedList_Equal(t *testing.T) {
+	lists := map[string]func(elements ...int) LinkedList[int]{
+		"singly": func(elements ...int) LinkedList[int] {
+			return NewSinglyLinkedList(elements...)
+		},
+		"doubly": func(elements ...int) LinkedList[int] {
+			return NewDoublyLinkedList(elements...)
+		},
+		"concurrent": func(elements ...int) LinkedList[int] {
+			l := NewDefaultConcurrentLinkedList[int]()
+			l.Add(elements...)
+			return l
+		},
+	}
+	type equaler interface {
+		Equal(other ReadOnlyList[int], eq func(a, b int) bool) bool
+		Hash(hash func(e int) uint64) uint64
+	}
+	for name, newList := range lists {
+		t.Run(name, func(t *testing.T) {
+			l := newList(1, 2, 3).(equaler)
+			assert.True(t, l.Equal(l.(ReadOnlyList[int]), nil))
+			for otherName, newOther := range lists {
+				assert.True(t, l.Equal(newOther(1, 2, 3), nil), otherName)
+				assert.False(t, l.Equal(newOther(1, 2), nil), otherName)
+				assert.False(t, l.Equal(newOther(1, 2, 4), nil), otherName)
+				assert.False(t, l.Equal(newOther(), nil), otherName)
+				assert.True(t, l.Equal(newOther(11, 12, 13), func(a, b int) bool {
+					return a%10 == b%10
+				}), otherName)
+				assert.Equal(t, l.Hash(hashInt), newOther(1, 2, 3).(equaler).Hash(hashInt), otherName)
+				assert.NotEqual(t, l.Hash(hashInt), newOther(3, 2, 1).(equaler).Hash(hashInt), otherName)
+			}
+			assert.True(t, l.Equal(NewTreeList(1, 2, 3), nil))
+		})
+	}
+}
+
+func TestSinglyLinkedList_EqualWithOption(t *testing.T) {
+	l := NewSinglyLinkedListWithOptions[string](option.WithEqual[string](strings.EqualFold))
+	l.Add("a", "B")
+	assert.True(t, l.Equal(NewDoublyLinkedList("A", "b"), nil))
+	d := NewDoublyLinkedListWithOptions[string](option.WithEqual[string](strings.EqualFold))
+	d.Add("a", "B")
+	assert.True(t, d.Equal(NewSinglyLinkedList("A", "b"), nil))
+	assert.False(t, d.Equal(NewSinglyLinkedList("A", "b"), func(a, b string) bool {
+		return a == b
+	}))
+	c := NewConcurrentLinkedList[string](d)
+	assert.True(t, c.Equal(NewSinglyLinkedList("A", "b"), nil))
+	tree := NewTreeListWithOptions[string](option.WithEqual[string](strings.EqualFold))
+	tree.Add("a", "B")
+	assert.True(t, NewConcurrentLinkedList[string](tree).Equal(NewSinglyLinkedList("A", "b"), nil))
+	assert.False(t, NewDefaultConcurrentLinkedList[string]().Equal(NewSinglyLinkedList("A"), nil))
+}
+
+func TestLinkedList_Clone(t *testing.T) {
+	type box struct {
+		v int
+	}
+	s := NewSinglyLinkedListWithOptions[*box](option.WithMaxSize[*box](3), option.WithOverflowPolicy[*box](option.OverflowEvictOldest))
+	s.Add(&box{1}, &box{2})
+	d := NewDoublyLinkedListWithOptions[*box](option.WithMaxSize[*box](3), option.WithOverflowPolicy[*box](option.OverflowEvictOldest))
+	d.Add(&box{1}, &box{2})
+	c := NewConcurrentLinkedList[*box](NewTreeListWithOptions[*box](option.WithMaxSize[*box](3), option.WithOverflowPolicy[*box](option.OverflowEvictOldest)))
+	c.Add(&box{1}, &box{2})
+	deepClone := func(b *box) *box {
+		return &box{b.v}
+	}
+	testCases := []struct {
+		name      string
+		list      LinkedList[*box]
+		clone     LinkedList[*box]
+		deepClone LinkedList[*box]
+	}{
+		{name: "singly", list: s, clone: s.Clone(), deepClone: s.DeepClone(deepClone)},
+		{name: "doubly", list: d, clone: d.Clone(), deepClone: d.DeepClone(deepClone)},
+		{name: "concurrent", list: c, clone: c.Clone(), deepClone: c.DeepClone(deepClone)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values := tc.list.Values()
+			// a shallow copy shares the elements
+			assert.Equal(t, values, tc.clone.Values())
+			assert.Same(t, values[0], tc.clone.Values()[0])
+			// a deep copy does not
+			assert.Equal(t, values, tc.deepClone.Values())
+			assert.NotSame(t, values[0], tc.deepClone.Values()[0])
+
+			// the copies keep the configuration and are independent of the list
+			tc.clone.Add(&box{3}, &box{4})
+			assert.Equal(t, []*box{values[1], {3}, {4}}, tc.clone.Values())
+			assert.Equal(t, values, tc.list.Values())
+			tc.list.Set(0, &box{5})
+			assert.Equal(t, 1, tc.deepClone.Values()[0].v)
+		})
+	}
+}
+
+func TestLinkedList_CloneWithoutObservers(t *testing.T) {
+	added := 0
+	l := NewDoublyLinkedListWithOptions[int](option.WithHooks[int](option.Hooks[int]{
+		OnAdd: func(int, int) {
+			added++
+		},
+	}))
+	l.Add(1)
+	c := l.Clone()
+	c.Add(2)
+	assert.Equal(t, 1, added)
+
+	// the lists of another package are copied into a SinglyLinkedList
+	concurrent := NewConcurrentLinkedList[int](readOnlyAdapter{NewDoublyLinkedList(1, 2)})
+	clone := concurrent.Clone()
+	assert.IsType(t, &SinglyLinkedList[int]{}, clone.list)
+	assert.Equal(t, []int{1, 2}, clone.Values())
+}
+
+// readOnlyAdapter hides the methods of the package of a list
+type readOnlyAdapter struct {
+	LinkedList[int]
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    []int
+		b    []int
+		want int
+	}{
+		{name: "empty", want: 0},
+		{name: "equal", a: []int{1, 2}, b: []int{1, 2}, want: 0},
+		{name: "lesser element", a: []int{1, 2}, b: []int{1, 3}, want: -1},
+		{name: "greater element", a: []int{2}, b: []int{1, 3}, want: 1},
+		{name: "prefix", a: []int{1}, b: []int{1, 2}, want: -1},
+		{name: "longer", a: []int{1, 2}, b: []int{1}, want: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Compare[int](NewSinglyLinkedList(tc.a...), NewDoublyLinkedList(tc.b...), cmp.Compare[int]))
+			concurrent := NewDefaultConcurrentLinkedList[int]()
+			concurrent.Add(tc.a...)
+			assert.Equal(t, tc.want, Compare[int](concurrent, NewTreeList(tc.b...), cmp.Compare[int]))
+			assert.Equal(t, -tc.want, Compare[int](NewTreeList(tc.b...), concurrent, cmp.Compare[int]))
+		})
+	}
+}
+
+func TestLinkedList_HashStable(t *testing.T) {
+	seed := maphash.MakeSeed()
+	hash := func(s string) uint64 {
+		return maphash.String(seed, s)
+	}
+	a := NewSinglyLinkedList("a", "b")
+	b := NewDoublyLinkedList("a", "b")
+	assert.Equal(t, a.Hash(hash), b.Hash(hash))
+	// the elements are not concatenated
+	assert.NotEqual(t, a.Hash(hash), NewSinglyLinkedList("ab").Hash(hash))
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import "github.com/chenmingyong0423/algorithms/errs"
+
+// The helpers below implement the error-returning variants of the LinkedList methods,
+// the callers must guarantee that the list is not changed concurrently.
+
+func getFirstE[T any](l LinkedList[T]) (T, error) {
+	t, ok := l.GetFirst()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+func getLastE[T any](l LinkedList[T]) (T, error) {
+	t, ok := l.GetLast()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+func getE[T any](l LinkedList[T], index int) (T, error) {
+	t, ok := l.Get(index)
+	if !ok {
+		return t, errs.ErrIndexOutOfRange{Index: index, Size: l.Size()}
+	}
+	return t, nil
+}
+
+func setE[T any](l LinkedList[T], index int, e T) error {
+	if !l.Set(index, e) {
+		return errs.ErrIndexOutOfRange{Index: index, Size: l.Size()}
+	}
+	return nil
+}
+
+func addE[T any](l LinkedList[T], elements ...T) error {
+	if l, ok := l.(interface{ AddE(elements ...T) error }); ok {
+		return l.AddE(elements...)
+	}
+	// the list does not report whether the elements are rejected
+	l.Add(elements...)
+	return nil
+}
+
+func insertE[T any](l LinkedList[T], index int, elements ...T) error {
+	size := l.Size()
+	// index 0 is valid for an empty list
+	if (index < 0 || index >= size) && (index != 0 || size != 0) {
+		return errs.ErrIndexOutOfRange{Index: index, Size: size}
+	}
+	if !l.Insert(index, elements...) {
+		return errs.ErrFull
+	}
+	return nil
+}
+
+func removeFirstE[T any](l LinkedList[T]) (T, error) {
+	t, ok := l.RemoveFirst()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+func removeLastE[T any](l LinkedList[T]) (T, error) {
+	t, ok := l.RemoveLast()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+func removeE[T any](l LinkedList[T], index int) (T, error) {
+	t, ok := l.Remove(index)
+	if !ok {
+		return t, errs.ErrIndexOutOfRange{Index: index, Size: l.Size()}
+	}
+	return t, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"sync"
+
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+// EventKind is the kind of change described by an Event.
+type EventKind int
+
+const (
+	EventAdd EventKind = iota + 1
+	EventRemove
+	EventSet
+	EventClear
+	EventReverse
+)
+
+// Event describes a change of a list.
+// Index and Value are set for EventAdd, EventRemove and EventSet, Old is only set for EventSet.
+type Event[T any] struct {
+	Kind  EventKind
+	Index int
+	Value T
+	Old   T
+}
+
+// feed forwards the events of a list to a channel.
+// The events are queued without limit so that a slow receiver never blocks the list.
+type feed[T any] struct {
+	lock   sync.Mutex
+	queue  []Event[T]
+	signal chan struct{}
+	done   chan struct{}
+	out    chan Event[T]
+}
+
+func newFeed[T any]() *feed[T] {
+	f := &feed[T]{
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		out:    make(chan Event[T]),
+	}
+	go f.run()
+	return f
+}
+
+// hooks returns the hooks pushing the changes to the feed
+func (f *feed[T]) hooks() option.Hooks[T] {
+	return option.Hooks[T]{
+		OnAdd: func(index int, e T) {
+			f.push(Event[T]{Kind: EventAdd, Index: index, Value: e})
+		},
+		OnRemove: func(index int, e T) {
+			f.push(Event[T]{Kind: EventRemove, Index: index, Value: e})
+		},
+		OnSet: func(index int, old, e T) {
+			f.push(Event[T]{Kind: EventSet, Index: index, Value: e, Old: old})
+		},
+		OnClear: func() {
+			f.push(Event[T]{Kind: EventClear})
+		},
+		OnReverse: func() {
+			f.push(Event[T]{Kind: EventReverse})
+		},
+	}
+}
+
+func (f *feed[T]) push(e Event[T]) {
+	f.lock.Lock()
+	f.queue = append(f.queue, e)
+	f.lock.Unlock()
+	select {
+	case f.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (f *feed[T]) run() {
+	defer close(f.out)
+	for {
+		select {
+		case <-f.signal:
+		case <-f.done:
+			return
+		}
+		f.lock.Lock()
+		events := f.queue
+		f.queue = nil
+		f.lock.Unlock()
+		for _, e := range events {
+			select {
+			case f.out <- e:
+			case <-f.done:
+				return
+			}
+		}
+	}
+}
+
+func (f *feed[T]) close() {
+	close(f.done)
+}
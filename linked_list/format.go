@@ -0,0 +1,107 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"fmt"
+
+	"github.com/chenmingyong0423/algorithms/internal/format"
+)
+
+var (
+	_ fmt.Formatter = (*SinglyLinkedList[any])(nil)
+	_ fmt.Formatter = (*DoublyLinkedList[any])(nil)
+	_ fmt.Formatter = (*TreeList[any])(nil)
+	_ fmt.Formatter = (*ConcurrentLinkedList[any])(nil)
+)
+
+// iterable is implemented by the lists of the package enumerating their elements without copying them
+type iterable[T any] interface {
+	each(fn func(index int, e T) bool)
+}
+
+// String returns the elements of the list, e.g. [1 2 3]
+func (l *SinglyLinkedList[T]) String() string {
+	return fmt.Sprint(l)
+}
+
+// GoString returns the Go syntax of the list, e.g. &linkedlist.SinglyLinkedList[int]{1, 2, 3}
+func (l *SinglyLinkedList[T]) GoString() string {
+	return fmt.Sprintf("%#v", l)
+}
+
+// Format implements fmt.Formatter: %v writes the elements, e.g. [1 2 3], %+v with their indices, e.g. [0:1 1:2 2:3],
+// and %#v the Go syntax. The precision limits the number of elements, e.g. %.2v writes [1 2 ... (1 more)],
+// and the width the length of the output, e.g. %80v. The other verbs are applied to the elements, e.g. %x.
+func (l *SinglyLinkedList[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, l, l.size, l.each)
+}
+
+// String returns the elements of the list, e.g. [1 2 3]
+func (l *DoublyLinkedList[T]) String() string {
+	return fmt.Sprint(l)
+}
+
+// GoString returns the Go syntax of the list, e.g. &linkedlist.DoublyLinkedList[int]{1, 2, 3}
+func (l *DoublyLinkedList[T]) GoString() string {
+	return fmt.Sprintf("%#v", l)
+}
+
+// Format implements fmt.Formatter, see SinglyLinkedList.Format
+func (l *DoublyLinkedList[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, l, l.size, l.each)
+}
+
+// String returns the elements of the list, e.g. [1 2 3]
+func (l *TreeList[T]) String() string {
+	return fmt.Sprint(l)
+}
+
+// GoString returns the Go syntax of the list, e.g. &linkedlist.TreeList[int]{1, 2, 3}
+func (l *TreeList[T]) GoString() string {
+	return fmt.Sprintf("%#v", l)
+}
+
+// Format implements fmt.Formatter, see SinglyLinkedList.Format
+func (l *TreeList[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, l, l.Size(), l.each)
+}
+
+// String returns the elements of the list, e.g. [1 2 3]
+func (l *ConcurrentLinkedList[T]) String() string {
+	return fmt.Sprint(l)
+}
+
+// GoString returns the Go syntax of the list, e.g. &linkedlist.ConcurrentLinkedList[int]{1, 2, 3}
+func (l *ConcurrentLinkedList[T]) GoString() string {
+	return fmt.Sprintf("%#v", l)
+}
+
+// Format implements fmt.Formatter under the read lock, see SinglyLinkedList.Format
+func (l *ConcurrentLinkedList[T]) Format(f fmt.State, verb rune) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	each := func(fn func(index int, e T) bool) {
+		for i, e := range l.list.Values() {
+			if !fn(i, e) {
+				return
+			}
+		}
+	}
+	if list, ok := l.list.(iterable[T]); ok {
+		each = list.each
+	}
+	format.Format(f, verb, l, l.list.Size(), each)
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkedList_Format(t *testing.T) {
+	testCases := []struct {
+		name   string
+		list   LinkedList[int]
+		goName string
+	}{
+		{name: "singly", list: NewSinglyLinkedList[int](), goName: "&linkedlist.SinglyLinkedList[int]"},
+		{name: "doubly", list: NewDoublyLinkedList[int](), goName: "&linkedlist.DoublyLinkedList[int]"},
+		{name: "tree", list: NewTreeList[int](), goName: "&linkedlist.TreeList[int]"},
+		{name: "concurrent", list: NewDefaultConcurrentLinkedList[int](), goName: "&linkedlist.ConcurrentLinkedList[int]"},
+		{
+			name:   "concurrent over a list without each",
+			list:   NewConcurrentLinkedList[int](NewConcurrentLinkedList[int](NewSinglyLinkedList[int]())),
+			goName: "&linkedlist.ConcurrentLinkedList[int]",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, "[]", fmt.Sprint(tc.list))
+			tc.list.Add(1, 2, 3, 4)
+			assert.Equal(t, "[1 2 3 4]", fmt.Sprint(tc.list))
+			assert.Equal(t, "[1 2 3 4]", tc.list.(fmt.Stringer).String())
+			assert.Equal(t, "[0:1 1:2 2:3 3:4]", fmt.Sprintf("%+v", tc.list))
+			assert.Equal(t, tc.goName+"{1, 2, 3, 4}", tc.list.(fmt.GoStringer).GoString())
+			assert.Equal(t, "[1 2 ... (2 more)]", fmt.Sprintf("%.2v", tc.list))
+			assert.Equal(t, "[1 ... (3 more)]", fmt.Sprintf("%17v", tc.list))
+			tc.list.Reverse()
+			assert.Equal(t, "[4 3 2 1]", fmt.Sprintf("%v", tc.list))
+		})
+	}
+}
@@ -14,21 +14,59 @@
 
 package linkedlist
 
+import (
+	"reflect"
+
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
 type LinkedList[T any] interface {
+	ReadOnlyList[T]
 	Add(elements ...T)
 	Append(elements ...T)
 	Prepend(elements ...T)
 	GetFirst() (T, bool)
 	GetLast() (T, bool)
-	Get(index int) (T, bool)
 	Set(index int, e T) bool
 	Insert(index int, elements ...T) bool
 	RemoveFirst() (T, bool)
 	RemoveLast() (T, bool)
 	Remove(index int) (T, bool)
+	Clear()
+	Reverse()
+}
+
+// ReadOnlyList is the read part of LinkedList, also implemented by the other sequences of the library.
+type ReadOnlyList[T any] interface {
+	Get(index int) (T, bool)
 	IsEmpty() bool
 	Size() int
-	Clear()
 	Values() []T
-	Reverse()
+}
+
+// Observable is implemented by the lists notifying observers of their changes.
+type Observable[T any] interface {
+	// Observe registers the hooks called after the list has been changed
+	// and returns a function unregistering them.
+	Observe(hooks option.Hooks[T]) (cancel func())
+}
+
+// defaultEqual compares the elements with ==, the elements which are not comparable are never equal
+func defaultEqual[T any](a, b T) bool {
+	x, y := any(a), any(b)
+	if x == nil || y == nil {
+		return x == y
+	}
+	// == panics on the values which are not comparable, e.g. slices or structs holding them
+	if !reflect.ValueOf(x).Comparable() || !reflect.ValueOf(y).Comparable() {
+		return false
+	}
+	return x == y
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
 }
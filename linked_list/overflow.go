@@ -0,0 +1,84 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import "github.com/chenmingyong0423/algorithms/option"
+
+// evictor is implemented by the lists that support the overflow policies
+type evictor[T any] interface {
+	Size() int
+	removeFirst() T
+	removeLast() T
+}
+
+// overflow applies the overflow policy of a list with a max size
+type overflow[T any] struct {
+	maxSize int
+	policy  option.OverflowPolicy
+	onEvict func(elements []T)
+}
+
+func newOverflow[T any](o option.Options[T]) overflow[T] {
+	return overflow[T]{
+		maxSize: o.MaxSize,
+		policy:  o.Overflow,
+		onEvict: o.OnEvict,
+	}
+}
+
+// makeRoom applies the overflow policy before the elements are added to the list.
+// It returns the elements to add, the number of elements evicted from the front of the list
+// and false if the operation is rejected.
+func (o overflow[T]) makeRoom(l evictor[T], elements []T) ([]T, int, bool) {
+	if o.maxSize <= 0 || l.Size()+len(elements) <= o.maxSize {
+		return elements, 0, true
+	}
+	var dropped []T
+	switch o.policy {
+	case option.OverflowEvictOldest:
+		front := min(l.Size()+len(elements)-o.maxSize, l.Size())
+		for i := 0; i < front; i++ {
+			dropped = append(dropped, l.removeFirst())
+		}
+		// not even the elements fit, keep the ones at the back
+		if len(elements) > o.maxSize {
+			dropped = append(dropped, elements[:len(elements)-o.maxSize]...)
+			elements = elements[len(elements)-o.maxSize:]
+		}
+		o.evict(dropped)
+		return elements, front, true
+	case option.OverflowEvictNewest:
+		back := min(l.Size()+len(elements)-o.maxSize, l.Size())
+		for i := 0; i < back; i++ {
+			dropped = append(dropped, l.removeLast())
+		}
+		// not even the elements fit, keep the ones at the front
+		if len(elements) > o.maxSize {
+			dropped = append(dropped, elements[o.maxSize:]...)
+			elements = elements[:o.maxSize]
+		}
+		o.evict(dropped)
+		return elements, 0, true
+	default:
+		o.evict(elements)
+		return nil, 0, false
+	}
+}
+
+func (o overflow[T]) evict(elements []T) {
+	if o.onEvict != nil && len(elements) > 0 {
+		o.onEvict(elements)
+	}
+}
@@ -14,7 +14,15 @@
 
 package linkedlist
 
-var _ LinkedList[any] = (*SinglyLinkedList[any])(nil)
+import (
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var (
+	_ LinkedList[any] = (*SinglyLinkedList[any])(nil)
+	_ Observable[any] = (*SinglyLinkedList[any])(nil)
+)
 
 type SinglyNode[T any] struct {
 	val  T
@@ -25,32 +33,40 @@ type SinglyLinkedList[T any] struct {
 	head *SinglyNode[T]
 	tail *SinglyNode[T]
 	size int
+
+	overflow  overflow[T]
+	equal     func(a, b T) bool
+	alloc     option.Allocator[SinglyNode[T]]
+	observers option.Observers[T]
 }
 
 // NewSinglyLinkedList returns a new singly linked list.
 // If the elements is not empty, add the elements to the list.
 func NewSinglyLinkedList[T any](elements ...T) *SinglyLinkedList[T] {
-	list := &SinglyLinkedList[T]{}
+	list := NewSinglyLinkedListWithOptions[T]()
 	if len(elements) > 0 {
 		list.Add(elements...)
 	}
 	return list
 }
 
+// NewSinglyLinkedListWithOptions returns a new singly linked list configured by opts.
+// The nodes are allocated by the option.Allocator[SinglyNode[T]] set by option.WithAllocator, if any.
+func NewSinglyLinkedListWithOptions[T any](opts ...option.Option[T]) *SinglyLinkedList[T] {
+	o := option.Apply(opts...)
+	list := &SinglyLinkedList[T]{
+		overflow:  newOverflow(o),
+		equal:     o.Equal,
+		observers: option.NewObservers(o.Hooks),
+		alloc:     option.AllocatorOf[SinglyNode[T]](o),
+	}
+	return list
+}
+
 // Add appends the specified elements to the end of the list.(same as Append)
+// If the list would exceed its max size, the overflow policy is applied.
 func (l *SinglyLinkedList[T]) Add(elements ...T) {
-	if len(elements) > 0 {
-		for _, e := range elements {
-			node := &SinglyNode[T]{val: e}
-			if l.IsEmpty() {
-				l.head, l.tail = node, node
-			} else {
-				l.tail.next = node
-				l.tail = node
-			}
-			l.size++
-		}
-	}
+	_ = l.AddE(elements...)
 }
 
 // Append appends the specified elements to the end of the list.(same as Add)
@@ -59,15 +75,10 @@ func (l *SinglyLinkedList[T]) Append(elements ...T) {
 }
 
 // Prepend prepends the specified elements to the beginning of the list.
+// If the list would exceed its max size, the overflow policy is applied.
 func (l *SinglyLinkedList[T]) Prepend(elements ...T) {
-	// reverse the elements. i.e. original elements: [2, 3], prepend elements: [0, 1], result: [0, 1, 2, 3]
-	for i := len(elements) - 1; i >= 0; i-- {
-		node := &SinglyNode[T]{val: elements[i], next: l.head}
-		l.head = node
-		if l.size == 0 {
-			l.tail = node
-		}
-		l.size++
+	if elements, _, ok := l.overflow.makeRoom(l, elements); ok {
+		l.prepend(elements...)
 	}
 }
 
@@ -95,10 +106,7 @@ func (l *SinglyLinkedList[T]) Get(index int) (t T, b bool) {
 	if l.isInvalidIndex(index) {
 		return
 	}
-	node := l.head
-	for i := 0; i < index; i, node = i+1, node.next {
-	}
-	return node.val, true
+	return l.node(index).val, true
 }
 
 // Set sets the element at the specified position in the list.
@@ -107,42 +115,29 @@ func (l *SinglyLinkedList[T]) Set(index int, e T) bool {
 	if l.isInvalidIndex(index) {
 		return false
 	}
-	node := l.head
-	for i := 0; i < index; i, node = i+1, node.next {
-	}
+	node := l.node(index)
+	old := node.val
 	node.val = e
+	l.observers.NotifySet(index, old, e)
 	return true
 }
 
 // Insert inserts the specified elements at the specified position in the list.
+// If the list would exceed its max size, the overflow policy is applied,
+// false is returned if the elements are rejected.
 func (l *SinglyLinkedList[T]) Insert(index int, elements ...T) bool {
-	if l.isInvalidIndex(index) {
-		if index == 0 {
-			l.Add(elements...)
-			return true
-		}
+	if l.isInvalidIndex(index) && (index != 0 || !l.IsEmpty()) {
 		return false
 	}
-
-	if index == 0 {
-		l.Prepend(elements...)
-		return true
+	// inserting at the last position appends the elements, except at 0 which always prepends
+	if index != 0 && index == l.Size()-1 {
+		index = l.Size()
 	}
-	if index == l.Size()-1 {
-		l.Append(elements...)
-		return true
-	}
-	prev := l.head
-	for i := 0; i < index-1; i, prev = i+1, prev.next {
-	}
-	oldNext := prev.next
-	for _, val := range elements {
-		node := &SinglyNode[T]{val: val}
-		prev.next = node
-		prev = node
-		l.size++
+	elements, evicted, ok := l.overflow.makeRoom(l, elements)
+	if !ok {
+		return false
 	}
-	prev.next = oldNext
+	l.insert(min(max(index-evicted, 0), l.Size()), elements...)
 	return true
 }
 
@@ -152,13 +147,7 @@ func (l *SinglyLinkedList[T]) RemoveFirst() (t T, b bool) {
 	if l.IsEmpty() {
 		return
 	}
-	node := l.head
-	l.head = node.next
-	l.size--
-	if l.IsEmpty() {
-		l.tail = nil
-	}
-	return node.val, true
+	return l.removeFirst(), true
 }
 
 // RemoveLast removes the last element from the list.
@@ -167,18 +156,7 @@ func (l *SinglyLinkedList[T]) RemoveLast() (t T, b bool) {
 	if l.IsEmpty() {
 		return
 	}
-	if l.Size() == 1 {
-		return l.RemoveFirst()
-	}
-	prev := l.head
-	for prev.next != l.tail {
-		prev = prev.next
-	}
-	node := prev.next
-	prev.next = nil
-	l.tail = prev
-	l.size--
-	return node.val, true
+	return l.removeLast(), true
 }
 
 // Remove removes the element at the specified position in the list.
@@ -187,22 +165,21 @@ func (l *SinglyLinkedList[T]) Remove(index int) (t T, b bool) {
 	if l.isInvalidIndex(index) {
 		return
 	}
-	if l.Size() == 1 {
-		return l.RemoveFirst()
+	if index == 0 {
+		return l.removeFirst(), true
 	}
 	if index == l.Size()-1 {
-		return l.RemoveLast()
+		return l.removeLast(), true
 	}
 
-	prev := l.head
 	// find the previous node of the node to be deleted
-	for i := 0; i < index-1; i, prev = i+1, prev.next {
-	}
-
+	prev := l.node(index - 1)
 	node := prev.next
 	prev.next = node.next
 	l.size--
-	t, node = node.val, nil
+	t = node.val
+	l.freeNode(node)
+	l.observers.NotifyRemove(index, t)
 	return t, true
 }
 
@@ -223,9 +200,17 @@ func (l *SinglyLinkedList[T]) isInvalidIndex(index int) bool {
 
 // Clear removes all the elements from the list
 func (l *SinglyLinkedList[T]) Clear() {
+	if l.alloc != nil {
+		for node := l.head; node != nil; {
+			next := node.next
+			l.freeNode(node)
+			node = next
+		}
+	}
 	l.head = nil
 	l.tail = nil
 	l.size = 0
+	l.observers.NotifyClear()
 }
 
 // Values returns a slice containing all the elements in this list.
@@ -239,10 +224,22 @@ func (l *SinglyLinkedList[T]) Values() []T {
 	return elements
 }
 
+// each calls fn for the elements in order, until fn returns false
+func (l *SinglyLinkedList[T]) each(fn func(index int, e T) bool) {
+	index := 0
+	for node := l.head; node != nil; node = node.next {
+		if !fn(index, node.val) {
+			return
+		}
+		index++
+	}
+}
+
 // Reverse reverses the list
 func (l *SinglyLinkedList[T]) Reverse() {
 	var prev *SinglyNode[T]
 	cur := l.head
+	l.tail = cur
 	for cur != nil {
 		next := cur.next
 		cur.next = prev
@@ -250,4 +247,199 @@ func (l *SinglyLinkedList[T]) Reverse() {
 		cur = next
 	}
 	l.head = prev
+	l.observers.NotifyReverse()
+}
+
+// GetFirstE returns the first element in the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *SinglyLinkedList[T]) GetFirstE() (T, error) {
+	return getFirstE[T](l)
+}
+
+// GetLastE returns the last element in the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *SinglyLinkedList[T]) GetLastE() (T, error) {
+	return getLastE[T](l)
+}
+
+// GetE returns the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *SinglyLinkedList[T]) GetE(index int) (T, error) {
+	return getE[T](l, index)
+}
+
+// SetE sets the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *SinglyLinkedList[T]) SetE(index int, e T) error {
+	return setE[T](l, index, e)
+}
+
+// AddE appends the specified elements to the end of the list.
+// If the elements are rejected because the list is full, errs.ErrFull is returned
+func (l *SinglyLinkedList[T]) AddE(elements ...T) error {
+	elements, _, ok := l.overflow.makeRoom(l, elements)
+	if !ok {
+		return errs.ErrFull
+	}
+	l.add(elements...)
+	return nil
+}
+
+// InsertE inserts the specified elements at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned,
+// if the elements are rejected because the list is full, errs.ErrFull is returned
+func (l *SinglyLinkedList[T]) InsertE(index int, elements ...T) error {
+	return insertE[T](l, index, elements...)
+}
+
+// RemoveFirstE removes the first element from the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *SinglyLinkedList[T]) RemoveFirstE() (T, error) {
+	return removeFirstE[T](l)
+}
+
+// RemoveLastE removes the last element from the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *SinglyLinkedList[T]) RemoveLastE() (T, error) {
+	return removeLastE[T](l)
+}
+
+// RemoveE removes the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *SinglyLinkedList[T]) RemoveE(index int) (T, error) {
+	return removeE[T](l, index)
+}
+
+// IndexOf returns the index of the first occurrence of the element in the list, or -1 if it is not found.
+// Elements are compared with the function set by option.WithEqual, or with == if there is none,
+// in which case the elements which are not comparable, e.g. slices, are never equal.
+func (l *SinglyLinkedList[T]) IndexOf(e T) int {
+	equal := l.equal
+	if equal == nil {
+		equal = defaultEqual[T]
+	}
+	for i, node := 0, l.head; node != nil; i, node = i+1, node.next {
+		if equal(node.val, e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains checks whether the list contains the element.
+func (l *SinglyLinkedList[T]) Contains(e T) bool {
+	return l.IndexOf(e) != -1
+}
+
+// Observe registers the hooks called after the list has been changed
+// and returns a function unregistering them.
+func (l *SinglyLinkedList[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	return l.observers.Observe(hooks)
+}
+
+// node returns the node at the specified position, the index must be valid
+func (l *SinglyLinkedList[T]) node(index int) *SinglyNode[T] {
+	if index == l.size-1 {
+		return l.tail
+	}
+	node := l.head
+	for i := 0; i < index; i, node = i+1, node.next {
+	}
+	return node
+}
+
+func (l *SinglyLinkedList[T]) newNode(e T, next *SinglyNode[T]) *SinglyNode[T] {
+	if l.alloc == nil {
+		return &SinglyNode[T]{val: e, next: next}
+	}
+	node := l.alloc.Alloc()
+	node.val, node.next = e, next
+	return node
+}
+
+func (l *SinglyLinkedList[T]) freeNode(node *SinglyNode[T]) {
+	if l.alloc != nil {
+		l.alloc.Free(node)
+	}
+}
+
+func (l *SinglyLinkedList[T]) add(elements ...T) {
+	for _, e := range elements {
+		node := l.newNode(e, nil)
+		if l.IsEmpty() {
+			l.head, l.tail = node, node
+		} else {
+			l.tail.next = node
+			l.tail = node
+		}
+		l.size++
+		l.observers.NotifyAdd(l.size-1, e)
+	}
+}
+
+func (l *SinglyLinkedList[T]) prepend(elements ...T) {
+	// reverse the elements. i.e. original elements: [2, 3], prepend elements: [0, 1], result: [0, 1, 2, 3]
+	for i := len(elements) - 1; i >= 0; i-- {
+		node := l.newNode(elements[i], l.head)
+		l.head = node
+		if l.size == 0 {
+			l.tail = node
+		}
+		l.size++
+		l.observers.NotifyAdd(0, elements[i])
+	}
+}
+
+// insert inserts the elements before the index, the index must be in [0, size]
+func (l *SinglyLinkedList[T]) insert(index int, elements ...T) {
+	if index == l.Size() {
+		l.add(elements...)
+		return
+	}
+	if index == 0 {
+		l.prepend(elements...)
+		return
+	}
+	prev := l.node(index - 1)
+	oldNext := prev.next
+	for i, e := range elements {
+		node := l.newNode(e, oldNext)
+		prev.next = node
+		prev = node
+		l.size++
+		l.observers.NotifyAdd(index+i, e)
+	}
+}
+
+// removeFirst removes the first element, the list must not be empty
+func (l *SinglyLinkedList[T]) removeFirst() T {
+	node := l.head
+	l.head = node.next
+	l.size--
+	if l.IsEmpty() {
+		l.tail = nil
+	}
+	t := node.val
+	l.freeNode(node)
+	l.observers.NotifyRemove(0, t)
+	return t
+}
+
+// removeLast removes the last element, the list must not be empty
+func (l *SinglyLinkedList[T]) removeLast() T {
+	if l.Size() == 1 {
+		return l.removeFirst()
+	}
+	prev := l.head
+	for prev.next != l.tail {
+		prev = prev.next
+	}
+	node := prev.next
+	prev.next = nil
+	l.tail = prev
+	l.size--
+	t := node.val
+	l.freeNode(node)
+	l.observers.NotifyRemove(l.size, t)
+	return t
 }
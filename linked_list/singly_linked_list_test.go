@@ -17,6 +17,8 @@ package linkedlist
 import (
 	"testing"
 
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -563,6 +565,14 @@ func TestSinglyLinkedList_Insert(t *testing.T) {
 			elements: []int{4, 5},
 			wantBool: false,
 		},
+		{
+			name:             "insert one element at the beginning of single-element list",
+			list:             NewSinglyLinkedList[int](1),
+			index:            0,
+			elements:         []int{9},
+			wantBool:         true,
+			wantListElements: []int{9, 1},
+		},
 		{
 			name:             "insert one element at the beginning of empty list",
 			list:             NewSinglyLinkedList[int](),
@@ -657,6 +667,401 @@ func TestSinglyLinkedList_Reverse(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.list.Reverse()
 			assert.Equal(t, tc.wantListElements, tc.list.Values())
+			if len(tc.wantListElements) > 0 {
+				last, _ := tc.list.GetLast()
+				assert.Equal(t, tc.wantListElements[len(tc.wantListElements)-1], last)
+			}
+		})
+	}
+}
+
+func TestNewSinglyLinkedListWithOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []option.Option[int]
+		elements []int
+
+		wantListElements []int
+		wantAdded        []int
+		wantRemoved      []int
+	}{
+		{
+			name:             "no options",
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name:             "max size",
+			opts:             []option.Option[int]{option.WithMaxSize[int](2)},
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 2},
+		},
+		{
+			name:             "allocator",
+			opts:             []option.Option[int]{option.WithAllocator[int](option.NewPoolAllocator[SinglyNode[int]]())},
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 3},
+			wantRemoved:      []int{2},
+		},
+		{
+			name:             "allocator of another node type",
+			opts:             []option.Option[int]{option.WithAllocator[int](option.NewPoolAllocator[DoublyNode[int]]())},
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 3},
+			wantRemoved:      []int{2},
+		},
+		{
+			name:             "hooks",
+			elements:         []int{1, 2, 3},
+			wantListElements: []int{1, 3},
+			wantAdded:        []int{1, 2, 3},
+			wantRemoved:      []int{2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var added, removed []int
+			hooks := option.Hooks[int]{
+				OnAdd: func(index int, e int) {
+					added = append(added, e)
+				},
+				OnRemove: func(index int, e int) {
+					assert.Equal(t, 1, index)
+					removed = append(removed, e)
+				},
+			}
+			opts := tc.opts
+			if tc.wantAdded != nil {
+				opts = append(opts, option.WithHooks[int](hooks))
+			}
+			list := NewSinglyLinkedListWithOptions[int](opts...)
+			for _, e := range tc.elements {
+				list.Add(e)
+			}
+			if tc.wantRemoved != nil {
+				list.Remove(1)
+			}
+			assert.Equal(t, tc.wantListElements, list.Values())
+			assert.Equal(t, tc.wantAdded, added)
+			if tc.wantAdded != nil {
+				assert.Equal(t, tc.wantRemoved, removed)
+			}
+		})
+	}
+}
+
+func TestSinglyLinkedList_IndexOf(t *testing.T) {
+	testCases := []struct {
+		name    string
+		list    *SinglyLinkedList[int]
+		element int
+
+		want int
+	}{
+		{
+			name:    "empty list",
+			list:    NewSinglyLinkedList[int](),
+			element: 1,
+			want:    -1,
+		},
+		{
+			name:    "element exists",
+			list:    NewSinglyLinkedList[int](1, 2, 3, 2),
+			element: 2,
+			want:    1,
+		},
+		{
+			name:    "element does not exist",
+			list:    NewSinglyLinkedList[int](1, 2, 3),
+			element: 4,
+			want:    -1,
+		},
+		{
+			name: "custom equal",
+			list: func() *SinglyLinkedList[int] {
+				l := NewSinglyLinkedListWithOptions[int](option.WithEqual[int](func(a, b int) bool {
+					return a%10 == b%10
+				}))
+				l.Add(1, 12, 3)
+				return l
+			}(),
+			element: 2,
+			want:    1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.list.IndexOf(tc.element))
+			assert.Equal(t, tc.want != -1, tc.list.Contains(tc.element))
+		})
+	}
+}
+
+func TestSinglyLinkedList_IndexOfNotComparable(t *testing.T) {
+	l := NewSinglyLinkedList[[]int]([]int{1})
+	assert.Equal(t, -1, l.IndexOf([]int{1}))
+	assert.False(t, l.Contains([]int{1}))
+
+	values := NewSinglyLinkedList[any]([]int{1}, nil, 2)
+	assert.Equal(t, -1, values.IndexOf([]int{1}))
+	assert.Equal(t, 1, values.IndexOf(nil))
+	assert.Equal(t, 2, values.IndexOf(2))
+}
+
+func TestSinglyLinkedList_Overflow(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy option.OverflowPolicy
+		op     func(l *SinglyLinkedList[int]) bool
+
+		wantBool         bool
+		wantListElements []int
+		wantEvicted      []int
+	}{
+		{
+			name:   "reject add",
+			policy: option.OverflowReject,
+			op: func(l *SinglyLinkedList[int]) bool {
+				l.Add(4)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{1, 2, 3},
+			wantEvicted:      []int{4},
+		},
+		{
+			name:   "reject insert",
+			policy: option.OverflowReject,
+			op: func(l *SinglyLinkedList[int]) bool {
+				return l.Insert(1, 4)
+			},
+			wantBool:         false,
+			wantListElements: []int{1, 2, 3},
+			wantEvicted:      []int{4},
+		},
+		{
+			name:   "block is treated as reject",
+			policy: option.OverflowBlock,
+			op: func(l *SinglyLinkedList[int]) bool {
+				return l.Insert(0, 4)
+			},
+			wantBool:         false,
+			wantListElements: []int{1, 2, 3},
+			wantEvicted:      []int{4},
+		},
+		{
+			name:   "evict oldest on add",
+			policy: option.OverflowEvictOldest,
+			op: func(l *SinglyLinkedList[int]) bool {
+				l.Add(4, 5)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{3, 4, 5},
+			wantEvicted:      []int{1, 2},
+		},
+		{
+			name:   "evict oldest on prepend",
+			policy: option.OverflowEvictOldest,
+			op: func(l *SinglyLinkedList[int]) bool {
+				l.Prepend(0)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{0, 2, 3},
+			wantEvicted:      []int{1},
+		},
+		{
+			name:   "evict oldest on insert",
+			policy: option.OverflowEvictOldest,
+			op: func(l *SinglyLinkedList[int]) bool {
+				return l.Insert(2, 4)
+			},
+			wantBool:         true,
+			wantListElements: []int{2, 3, 4},
+			wantEvicted:      []int{1},
+		},
+		{
+			name:   "evict oldest with more elements than max size",
+			policy: option.OverflowEvictOldest,
+			op: func(l *SinglyLinkedList[int]) bool {
+				l.Add(4, 5, 6, 7)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{5, 6, 7},
+			wantEvicted:      []int{1, 2, 3, 4},
+		},
+		{
+			name:   "evict newest on add",
+			policy: option.OverflowEvictNewest,
+			op: func(l *SinglyLinkedList[int]) bool {
+				l.Add(4)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{1, 2, 4},
+			wantEvicted:      []int{3},
+		},
+		{
+			name:   "evict newest on insert",
+			policy: option.OverflowEvictNewest,
+			op: func(l *SinglyLinkedList[int]) bool {
+				return l.Insert(1, 4, 5)
+			},
+			wantBool:         true,
+			wantListElements: []int{1, 4, 5},
+			wantEvicted:      []int{3, 2},
+		},
+		{
+			name:   "evict newest with more elements than max size",
+			policy: option.OverflowEvictNewest,
+			op: func(l *SinglyLinkedList[int]) bool {
+				l.Prepend(4, 5, 6, 7)
+				return true
+			},
+			wantBool:         true,
+			wantListElements: []int{4, 5, 6},
+			wantEvicted:      []int{3, 2, 1, 7},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []int
+			l := NewSinglyLinkedListWithOptions[int](
+				option.WithMaxSize[int](3),
+				option.WithOverflowPolicy[int](tc.policy),
+				option.WithOnEvict[int](func(elements []int) {
+					evicted = append(evicted, elements...)
+				}),
+			)
+			l.Add(1, 2, 3)
+			assert.Equal(t, tc.wantBool, tc.op(l))
+			assert.Equal(t, tc.wantListElements, l.Values())
+			assert.Equal(t, tc.wantEvicted, evicted)
+		})
+	}
+}
+
+func TestSinglyLinkedList_Observe(t *testing.T) {
+	var events []Event[int]
+	l := NewSinglyLinkedList[int](1, 2, 3)
+	cancel := l.Observe(newFeedHooks(&events))
+
+	l.Add(4)
+	l.Insert(1, 5)
+	l.Set(0, 6)
+	l.Remove(2)
+	l.Reverse()
+	l.Clear()
+	cancel()
+	l.Add(7)
+
+	assert.Equal(t, []Event[int]{
+		{Kind: EventAdd, Index: 3, Value: 4},
+		{Kind: EventAdd, Index: 1, Value: 5},
+		{Kind: EventSet, Index: 0, Value: 6, Old: 1},
+		{Kind: EventRemove, Index: 2, Value: 2},
+		{Kind: EventReverse},
+		{Kind: EventClear},
+	}, events)
+}
+
+func TestSinglyLinkedList_ErrorVariants(t *testing.T) {
+	empty := NewSinglyLinkedListWithOptions[int]()
+	_, err := empty.GetFirstE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.GetLastE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.RemoveFirstE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.RemoveLastE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = empty.GetE(0)
+	assert.Equal(t, errs.ErrIndexOutOfRange{Index: 0, Size: 0}, err)
+	assert.NoError(t, empty.InsertE(0, 1, 2, 3))
+
+	testCases := []struct {
+		name string
+		op   func(l *SinglyLinkedList[int]) error
+
+		wantErr          error
+		wantListElements []int
+	}{
+		{
+			name: "get",
+			op: func(l *SinglyLinkedList[int]) error {
+				_, err := l.GetE(1)
+				return err
+			},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "get with invalid index",
+			op: func(l *SinglyLinkedList[int]) error {
+				_, err := l.GetE(3)
+				return err
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: 3, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "set with invalid index",
+			op: func(l *SinglyLinkedList[int]) error {
+				return l.SetE(-1, 0)
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: -1, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "insert with invalid index",
+			op: func(l *SinglyLinkedList[int]) error {
+				return l.InsertE(4, 0)
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: 4, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "add into full list",
+			op: func(l *SinglyLinkedList[int]) error {
+				return l.AddE(0, 0)
+			},
+			wantErr:          errs.ErrFull,
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "insert into full list",
+			op: func(l *SinglyLinkedList[int]) error {
+				return l.InsertE(0, 0, 0)
+			},
+			wantErr:          errs.ErrFull,
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name: "remove",
+			op: func(l *SinglyLinkedList[int]) error {
+				_, err := l.RemoveE(1)
+				return err
+			},
+			wantListElements: []int{1, 3},
+		},
+		{
+			name: "remove with invalid index",
+			op: func(l *SinglyLinkedList[int]) error {
+				_, err := l.RemoveE(3)
+				return err
+			},
+			wantErr:          errs.ErrIndexOutOfRange{Index: 3, Size: 3},
+			wantListElements: []int{1, 2, 3},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewSinglyLinkedListWithOptions[int](option.WithMaxSize[int](4))
+			l.Add(1, 2, 3)
+			err := tc.op(l)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantListElements, l.Values())
 		})
 	}
 }
@@ -0,0 +1,466 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"math/rand"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var (
+	_ LinkedList[any] = (*TreeList[any])(nil)
+	_ Observable[any] = (*TreeList[any])(nil)
+)
+
+// treeNode is a node of an implicit treap, ordered by position and heap ordered by priority
+type treeNode[T any] struct {
+	val      T
+	priority uint32
+	size     int
+	// reversed marks that the children of the node have to be reversed
+	reversed bool
+	left     *treeNode[T]
+	right    *treeNode[T]
+}
+
+// TreeList is a LinkedList backed by an implicit treap, a randomized balanced tree ordered by position.
+// Get, Set, Insert and Remove are O(log n), Reverse is O(1).
+// The read methods do not change the tree, so TreeList can be wrapped by ConcurrentLinkedList.
+type TreeList[T any] struct {
+	root *treeNode[T]
+
+	overflow  overflow[T]
+	equal     func(a, b T) bool
+	observers option.Observers[T]
+}
+
+// NewTreeList returns a new tree list.
+// If the elements is not empty, add the elements to the list.
+func NewTreeList[T any](elements ...T) *TreeList[T] {
+	list := NewTreeListWithOptions[T]()
+	if len(elements) > 0 {
+		list.Add(elements...)
+	}
+	return list
+}
+
+// NewTreeListWithOptions returns a new tree list configured by opts.
+// The capacity and the allocator are ignored.
+func NewTreeListWithOptions[T any](opts ...option.Option[T]) *TreeList[T] {
+	o := option.Apply(opts...)
+	return &TreeList[T]{
+		overflow:  newOverflow(o),
+		equal:     o.Equal,
+		observers: option.NewObservers(o.Hooks),
+	}
+}
+
+// Add appends the specified elements to the end of the list.(same as Append)
+// If the list would exceed its max size, the overflow policy is applied.
+func (l *TreeList[T]) Add(elements ...T) {
+	_ = l.AddE(elements...)
+}
+
+// Append appends the specified elements to the end of the list.(same as Add)
+func (l *TreeList[T]) Append(elements ...T) {
+	l.Add(elements...)
+}
+
+// Prepend prepends the specified elements to the beginning of the list.
+// If the list would exceed its max size, the overflow policy is applied.
+func (l *TreeList[T]) Prepend(elements ...T) {
+	if elements, _, ok := l.overflow.makeRoom(l, elements); ok {
+		l.insert(0, elements...)
+	}
+}
+
+// GetFirst returns the first element in the list.
+// If the list is empty, b return false
+func (l *TreeList[T]) GetFirst() (t T, b bool) {
+	return l.Get(0)
+}
+
+// GetLast returns the last element in the list.
+// If the list is empty, b return false
+func (l *TreeList[T]) GetLast() (t T, b bool) {
+	return l.Get(l.Size() - 1)
+}
+
+// Get returns the element at the specified position in the list.
+// If the index is invalid, b return false
+func (l *TreeList[T]) Get(index int) (t T, b bool) {
+	if l.isInvalidIndex(index) {
+		return
+	}
+	return l.node(index).val, true
+}
+
+// Set sets the element at the specified position in the list.
+// If the index is invalid, b return false
+func (l *TreeList[T]) Set(index int, e T) bool {
+	if l.isInvalidIndex(index) {
+		return false
+	}
+	node := l.node(index)
+	old := node.val
+	node.val = e
+	l.observers.NotifySet(index, old, e)
+	return true
+}
+
+// Insert inserts the specified elements at the specified position in the list.
+// If the list would exceed its max size, the overflow policy is applied,
+// false is returned if the elements are rejected.
+func (l *TreeList[T]) Insert(index int, elements ...T) bool {
+	if l.isInvalidIndex(index) && (index != 0 || !l.IsEmpty()) {
+		return false
+	}
+	// inserting at the last position appends the elements, except at 0 which always prepends
+	if index != 0 && index == l.Size()-1 {
+		index = l.Size()
+	}
+	elements, evicted, ok := l.overflow.makeRoom(l, elements)
+	if !ok {
+		return false
+	}
+	l.insert(min(max(index-evicted, 0), l.Size()), elements...)
+	return true
+}
+
+// RemoveFirst removes the first element from the list.
+// If the list is empty, b return false
+func (l *TreeList[T]) RemoveFirst() (t T, b bool) {
+	if l.IsEmpty() {
+		return
+	}
+	return l.removeFirst(), true
+}
+
+// RemoveLast removes the last element from the list.
+// If the list is empty, b return false
+func (l *TreeList[T]) RemoveLast() (t T, b bool) {
+	if l.IsEmpty() {
+		return
+	}
+	return l.removeLast(), true
+}
+
+// Remove removes the element at the specified position in the list.
+// If the index is invalid, b return false
+func (l *TreeList[T]) Remove(index int) (t T, b bool) {
+	if l.isInvalidIndex(index) {
+		return
+	}
+	return l.remove(index), true
+}
+
+// IsEmpty checks whether the list is empty
+func (l *TreeList[T]) IsEmpty() bool {
+	return l.Size() == 0
+}
+
+// Size returns the size of the list
+func (l *TreeList[T]) Size() int {
+	return l.root.len()
+}
+
+// Clear removes all the elements from the list
+func (l *TreeList[T]) Clear() {
+	l.root = nil
+	l.observers.NotifyClear()
+}
+
+// Values returns a slice containing all the elements in this list.
+func (l *TreeList[T]) Values() []T {
+	elements := make([]T, 0, l.Size())
+	l.each(func(_ int, e T) bool {
+		elements = append(elements, e)
+		return true
+	})
+	return elements
+}
+
+// Reverse reverses the list
+func (l *TreeList[T]) Reverse() {
+	if l.root != nil {
+		l.root.reversed = !l.root.reversed
+	}
+	l.observers.NotifyReverse()
+}
+
+// GetFirstE returns the first element in the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *TreeList[T]) GetFirstE() (T, error) {
+	return getFirstE[T](l)
+}
+
+// GetLastE returns the last element in the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *TreeList[T]) GetLastE() (T, error) {
+	return getLastE[T](l)
+}
+
+// GetE returns the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *TreeList[T]) GetE(index int) (T, error) {
+	return getE[T](l, index)
+}
+
+// SetE sets the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *TreeList[T]) SetE(index int, e T) error {
+	return setE[T](l, index, e)
+}
+
+// AddE appends the specified elements to the end of the list.
+// If the elements are rejected because the list is full, errs.ErrFull is returned
+func (l *TreeList[T]) AddE(elements ...T) error {
+	elements, _, ok := l.overflow.makeRoom(l, elements)
+	if !ok {
+		return errs.ErrFull
+	}
+	l.insert(l.Size(), elements...)
+	return nil
+}
+
+// InsertE inserts the specified elements at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned,
+// if the elements are rejected because the list is full, errs.ErrFull is returned
+func (l *TreeList[T]) InsertE(index int, elements ...T) error {
+	return insertE[T](l, index, elements...)
+}
+
+// RemoveFirstE removes the first element from the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *TreeList[T]) RemoveFirstE() (T, error) {
+	return removeFirstE[T](l)
+}
+
+// RemoveLastE removes the last element from the list.
+// If the list is empty, errs.ErrEmpty is returned
+func (l *TreeList[T]) RemoveLastE() (T, error) {
+	return removeLastE[T](l)
+}
+
+// RemoveE removes the element at the specified position in the list.
+// If the index is invalid, errs.ErrIndexOutOfRange is returned
+func (l *TreeList[T]) RemoveE(index int) (T, error) {
+	return removeE[T](l, index)
+}
+
+// IndexOf returns the index of the first occurrence of the element in the list, or -1 if it is not found.
+// Elements are compared with the function set by option.WithEqual, or with == if there is none,
+// in which case the elements which are not comparable, e.g. slices, are never equal.
+func (l *TreeList[T]) IndexOf(e T) int {
+	equal := l.equal
+	if equal == nil {
+		equal = defaultEqual[T]
+	}
+	index := -1
+	l.each(func(i int, v T) bool {
+		if equal(v, e) {
+			index = i
+			return false
+		}
+		return true
+	})
+	return index
+}
+
+// Contains checks whether the list contains the element.
+func (l *TreeList[T]) Contains(e T) bool {
+	return l.IndexOf(e) != -1
+}
+
+// Observe registers the hooks called after the list has been changed
+// and returns a function unregistering them.
+func (l *TreeList[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	return l.observers.Observe(hooks)
+}
+
+// isInvalidIndex checks whether the index is invalid
+func (l *TreeList[T]) isInvalidIndex(index int) bool {
+	return index < 0 || index > l.Size()-1
+}
+
+// node returns the node at the specified position without changing the tree, the index must be valid
+func (l *TreeList[T]) node(index int) *treeNode[T] {
+	node, flip := l.root, false
+	for {
+		flip = flip != node.reversed
+		left, right := node.children(flip)
+		switch size := left.len(); {
+		case index < size:
+			node = left
+		case index == size:
+			return node
+		default:
+			index -= size + 1
+			node = right
+		}
+	}
+}
+
+// each calls fn for the elements in order without changing the tree, until fn returns false
+func (l *TreeList[T]) each(fn func(index int, e T) bool) {
+	type frame struct {
+		node *treeNode[T]
+		flip bool
+	}
+	var stack []frame
+	node, flip, index := l.root, false, 0
+	for node != nil || len(stack) > 0 {
+		for node != nil {
+			flip = flip != node.reversed
+			stack = append(stack, frame{node: node, flip: flip})
+			node, _ = node.children(flip)
+		}
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(index, top.node.val) {
+			return
+		}
+		index++
+		_, node = top.node.children(top.flip)
+		flip = top.flip
+	}
+}
+
+// insert inserts the elements before the index, the index must be in [0, size]
+func (l *TreeList[T]) insert(index int, elements ...T) {
+	if len(elements) == 0 {
+		return
+	}
+	left, right := split(l.root, index)
+	l.root = merge(merge(left, build(elements)), right)
+	for i, e := range elements {
+		l.observers.NotifyAdd(index+i, e)
+	}
+}
+
+// remove removes the element at the index, the index must be valid
+func (l *TreeList[T]) remove(index int) T {
+	left, right := split(l.root, index)
+	node, right := split(right, 1)
+	l.root = merge(left, right)
+	l.observers.NotifyRemove(index, node.val)
+	return node.val
+}
+
+// removeFirst removes the first element, the list must not be empty
+func (l *TreeList[T]) removeFirst() T {
+	return l.remove(0)
+}
+
+// removeLast removes the last element, the list must not be empty
+func (l *TreeList[T]) removeLast() T {
+	return l.remove(l.Size() - 1)
+}
+
+func (n *treeNode[T]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// children returns the children in order, flip tells whether the subtree of the node is reversed
+func (n *treeNode[T]) children(flip bool) (*treeNode[T], *treeNode[T]) {
+	if flip {
+		return n.right, n.left
+	}
+	return n.left, n.right
+}
+
+// push applies the pending reversal of the node to its children
+func (n *treeNode[T]) push() {
+	if n.reversed {
+		n.left, n.right = n.right, n.left
+		if n.left != nil {
+			n.left.reversed = !n.left.reversed
+		}
+		if n.right != nil {
+			n.right.reversed = !n.right.reversed
+		}
+		n.reversed = false
+	}
+}
+
+func (n *treeNode[T]) update() {
+	n.size = 1 + n.left.len() + n.right.len()
+}
+
+// split splits the tree into the first k nodes and the others
+func split[T any](n *treeNode[T], k int) (*treeNode[T], *treeNode[T]) {
+	if n == nil {
+		return nil, nil
+	}
+	n.push()
+	if n.left.len() < k {
+		left, right := split(n.right, k-n.left.len()-1)
+		n.right = left
+		n.update()
+		return n, right
+	}
+	left, right := split(n.left, k)
+	n.left = right
+	n.update()
+	return left, n
+}
+
+// merge concatenates two trees
+func merge[T any](a, b *treeNode[T]) *treeNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.push()
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.push()
+	b.left = merge(a, b.left)
+	b.update()
+	return b
+}
+
+// build builds a tree of the elements in O(n) by keeping the right spine on a stack
+func build[T any](elements []T) *treeNode[T] {
+	spine := make([]*treeNode[T], 0, 32)
+	for _, e := range elements {
+		node := &treeNode[T]{val: e, priority: rand.Uint32(), size: 1}
+		var last *treeNode[T]
+		for len(spine) > 0 && spine[len(spine)-1].priority < node.priority {
+			last = spine[len(spine)-1]
+			spine = spine[:len(spine)-1]
+			last.update()
+		}
+		node.left = last
+		if len(spine) > 0 {
+			spine[len(spine)-1].right = node
+		}
+		spine = append(spine, node)
+	}
+	for i := len(spine) - 1; i >= 0; i-- {
+		spine[i].update()
+	}
+	return spine[0]
+}
@@ -0,0 +1,244 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeList_Insert(t *testing.T) {
+	testCases := []struct {
+		name     string
+		list     *TreeList[int]
+		index    int
+		elements []int
+
+		wantBool         bool
+		wantListElements []int
+	}{
+		{
+			name:             "index is less than zero",
+			list:             NewTreeList[int](1, 2, 5),
+			index:            -1,
+			elements:         []int{3, 4},
+			wantBool:         false,
+			wantListElements: []int{1, 2, 5},
+		},
+		{
+			name:             "index is greater than size of list",
+			list:             NewTreeList[int](1, 2, 3),
+			index:            3,
+			elements:         []int{4, 5},
+			wantBool:         false,
+			wantListElements: []int{1, 2, 3},
+		},
+		{
+			name:             "insert one element at the beginning of single-element list",
+			list:             NewTreeList[int](1),
+			index:            0,
+			elements:         []int{9},
+			wantBool:         true,
+			wantListElements: []int{9, 1},
+		},
+		{
+			name:             "insert one element at the beginning of empty list",
+			list:             NewTreeList[int](),
+			index:            0,
+			elements:         []int{1},
+			wantBool:         true,
+			wantListElements: []int{1},
+		},
+		{
+			name:             "insert multiple elements at the beginning of the list",
+			list:             NewTreeList[int](3, 4),
+			index:            0,
+			elements:         []int{1, 2},
+			wantBool:         true,
+			wantListElements: []int{1, 2, 3, 4},
+		},
+		{
+			name:             "insert multiple elements at the end of the list",
+			list:             NewTreeList[int](1, 2, 3),
+			index:            2,
+			elements:         []int{4, 5},
+			wantBool:         true,
+			wantListElements: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:             "insert multiple elements in the middle of the list",
+			list:             NewTreeList[int](1, 2, 5, 6),
+			index:            2,
+			elements:         []int{3, 4},
+			wantBool:         true,
+			wantListElements: []int{1, 2, 3, 4, 5, 6},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.list.Insert(tc.index, tc.elements...)
+			assert.Equal(t, tc.wantBool, got)
+			assert.Equal(t, tc.wantListElements, tc.list.Values())
+		})
+	}
+}
+
+func TestTreeList_Reverse(t *testing.T) {
+	testCases := []struct {
+		name string
+		list *TreeList[int]
+
+		wantListElements []int
+	}{
+		{
+			name:             "empty list",
+			list:             NewTreeList[int](),
+			wantListElements: []int{},
+		},
+		{
+			name:             "list with one element",
+			list:             NewTreeList[int](1),
+			wantListElements: []int{1},
+		},
+		{
+			name:             "list with more than one element",
+			list:             NewTreeList[int](1, 2, 3, 4, 5),
+			wantListElements: []int{5, 4, 3, 2, 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.list.Reverse()
+			assert.Equal(t, tc.wantListElements, tc.list.Values())
+			for i, want := range tc.wantListElements {
+				got, ok := tc.list.Get(i)
+				assert.True(t, ok)
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
+
+func TestTreeList_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	l := NewTreeList[int]()
+	var want []int
+	for i := 0; i < 5000; i++ {
+		switch op := r.Intn(8); {
+		case op == 0 || len(want) == 0:
+			e := r.Int()
+			l.Prepend(e)
+			want = append([]int{e}, want...)
+		case op == 1:
+			e := r.Int()
+			l.Add(e, e+1)
+			want = append(want, e, e+1)
+		case op == 2:
+			idx, e := r.Intn(len(want)), r.Int()
+			l.Insert(idx, e)
+			if idx == len(want)-1 {
+				idx++
+			}
+			want = append(want[:idx], append([]int{e}, want[idx:]...)...)
+		case op == 3:
+			idx := r.Intn(len(want))
+			got, _ := l.Remove(idx)
+			assert.Equal(t, want[idx], got)
+			want = append(want[:idx], want[idx+1:]...)
+		case op == 4:
+			idx, e := r.Intn(len(want)), r.Int()
+			l.Set(idx, e)
+			want[idx] = e
+		case op == 5:
+			l.Reverse()
+			for i, j := 0, len(want)-1; i < j; i, j = i+1, j-1 {
+				want[i], want[j] = want[j], want[i]
+			}
+		case op == 6:
+			got, _ := l.RemoveLast()
+			assert.Equal(t, want[len(want)-1], got)
+			want = want[:len(want)-1]
+		default:
+			idx := r.Intn(len(want))
+			got, _ := l.Get(idx)
+			assert.Equal(t, want[idx], got)
+		}
+		assert.Equal(t, len(want), l.Size())
+	}
+	assert.Equal(t, want, l.Values())
+	if len(want) > 0 {
+		assert.Equal(t, 0, l.IndexOf(want[0]))
+	}
+}
+
+func TestTreeList_Options(t *testing.T) {
+	var evicted []int
+	var events []Event[int]
+	l := NewTreeListWithOptions[int](
+		option.WithMaxSize[int](3),
+		option.WithOverflowPolicy[int](option.OverflowEvictOldest),
+		option.WithOnEvict[int](func(elements []int) {
+			evicted = append(evicted, elements...)
+		}),
+	)
+	l.Add(1, 2, 3)
+	l.Observe(newFeedHooks(&events))
+	assert.NoError(t, l.AddE(4))
+	assert.Equal(t, []int{2, 3, 4}, l.Values())
+	assert.Equal(t, []int{1}, evicted)
+	assert.Equal(t, []Event[int]{
+		{Kind: EventRemove, Index: 0, Value: 1},
+		{Kind: EventAdd, Index: 2, Value: 4},
+	}, events)
+}
+
+func TestTreeList_AddE(t *testing.T) {
+	l := NewTreeListWithOptions[int](option.WithMaxSize[int](2))
+	assert.NoError(t, l.AddE(1, 2))
+	assert.Equal(t, errs.ErrFull, l.AddE(3))
+	assert.Equal(t, []int{1, 2}, l.Values())
+}
+
+func TestTreeList_Concurrent(t *testing.T) {
+	l := NewConcurrentLinkedList[int](NewTreeList[int]())
+	l.Add(1, 2, 3)
+	l.Reverse()
+	assert.Equal(t, []int{3, 2, 1}, l.Values())
+	v, err := l.GetE(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func BenchmarkTreeList_Get(b *testing.B) {
+	const size = 10000
+	elements := make([]int, size)
+	doubly := NewDoublyLinkedList[int](elements...)
+	tree := NewTreeList[int](elements...)
+	r := rand.New(rand.NewSource(1))
+	b.Run("doubly random", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			doubly.Get(r.Intn(size))
+		}
+	})
+	b.Run("tree random", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree.Get(r.Intn(size))
+		}
+	})
+}
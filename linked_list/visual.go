@@ -0,0 +1,70 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import "github.com/chenmingyong0423/algorithms/visual"
+
+// Graph returns the nodes of the list, as found by walking them from the head, see visual.Chain.
+func (l *SinglyLinkedList[T]) Graph(opts ...visual.Option[T]) visual.Graph {
+	return visual.Chain[*SinglyNode[T], T]{
+		Head: l.head,
+		Tail: l.tail,
+		Size: l.size,
+		Next: func(n *SinglyNode[T]) *SinglyNode[T] {
+			return n.next
+		},
+		Value: func(n *SinglyNode[T]) T {
+			return n.val
+		},
+	}.Walk(visual.KindSingly, visual.Apply("SinglyLinkedList", opts...))
+}
+
+// DOT renders the nodes of the list in the Graphviz DOT language, see visual.DOT.
+func (l *SinglyLinkedList[T]) DOT(opts ...visual.Option[T]) string {
+	return visual.DOT(l.Graph(opts...))
+}
+
+// ASCII renders the nodes of the list as a diagram for the terminal, see visual.ASCII.
+func (l *SinglyLinkedList[T]) ASCII(opts ...visual.Option[T]) string {
+	return visual.ASCII(l.Graph(opts...))
+}
+
+// Graph returns the nodes of the list, as found by walking them from the head, see visual.Chain.
+func (l *DoublyLinkedList[T]) Graph(opts ...visual.Option[T]) visual.Graph {
+	return visual.Chain[*DoublyNode[T], T]{
+		Head: l.head,
+		Tail: l.tail,
+		Size: l.size,
+		Next: func(n *DoublyNode[T]) *DoublyNode[T] {
+			return n.next
+		},
+		Prev: func(n *DoublyNode[T]) *DoublyNode[T] {
+			return n.prev
+		},
+		Value: func(n *DoublyNode[T]) T {
+			return n.val
+		},
+	}.Walk(visual.KindDoubly, visual.Apply("DoublyLinkedList", opts...))
+}
+
+// DOT renders the nodes of the list in the Graphviz DOT language, see visual.DOT.
+func (l *DoublyLinkedList[T]) DOT(opts ...visual.Option[T]) string {
+	return visual.DOT(l.Graph(opts...))
+}
+
+// ASCII renders the nodes of the list as a diagram for the terminal, see visual.ASCII.
+func (l *DoublyLinkedList[T]) ASCII(opts ...visual.Option[T]) string {
+	return visual.ASCII(l.Graph(opts...))
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkedlist
+
+import (
+	"testing"
+
+	"github.com/chenmingyong0423/algorithms/visual"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinglyLinkedList_ASCII(t *testing.T) {
+	testCases := []struct {
+		name string
+		list func() *SinglyLinkedList[int]
+		opts []visual.Option[int]
+		want string
+	}{
+		{
+			name: "empty",
+			list: func() *SinglyLinkedList[int] {
+				return NewSinglyLinkedList[int]()
+			},
+			want: "head,tail\n" +
+				"|\n" +
+				"v\n" +
+				"nil\n",
+		},
+		{
+			name: "elements",
+			list: func() *SinglyLinkedList[int] {
+				return NewSinglyLinkedList(1, 2, 3)
+			},
+			opts: []visual.Option[int]{visual.WithFormat(func(e int) string {
+				return string(rune('a' + e - 1))
+			})},
+			want: "head          tail\n" +
+				"|             |\n" +
+				"v             v\n" +
+				"[a] -> [b] -> [c] -> nil\n",
+		},
+		{
+			name: "max nodes",
+			list: func() *SinglyLinkedList[int] {
+				return NewSinglyLinkedList(1, 2, 3, 4)
+			},
+			opts: []visual.Option[int]{visual.WithMaxNodes[int](2)},
+			want: "head          tail\n" +
+				"|             |\n" +
+				"v             v\n" +
+				"[1] -> [2] -> ... (2 more)\n",
+		},
+		{
+			name: "cycle",
+			list: func() *SinglyLinkedList[int] {
+				l := NewSinglyLinkedList(1, 2, 3)
+				l.tail.next = l.head.next
+				return l
+			},
+			want: "head          tail\n" +
+				"|             |\n" +
+				"v             v\n" +
+				"[1] -> [2] -> [3] -x #1\n" +
+				"! next of #2 (3) points back to #1 (2), the list has a cycle\n",
+		},
+		{
+			name: "broken tail and size",
+			list: func() *SinglyLinkedList[int] {
+				l := NewSinglyLinkedList(1, 2, 3)
+				l.tail = l.head.next
+				l.size = 4
+				return l
+			},
+			want: "head   tail!\n" +
+				"|      |\n" +
+				"v      x\n" +
+				"[1] -> [2] -> [3] -> nil\n" +
+				"! tail points to #1 (2), want #2 (3)\n" +
+				"! size is 4 but 3 nodes are reachable from head\n",
+		},
+		{
+			name: "without check",
+			list: func() *SinglyLinkedList[int] {
+				l := NewSinglyLinkedList(1, 2, 3)
+				l.tail.next = l.head
+				return l
+			},
+			opts: []visual.Option[int]{visual.WithCheck[int](false)},
+			want: "head          tail\n" +
+				"|             |\n" +
+				"v             v\n" +
+				"[1] -> [2] -> [3] -> nil\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.list().ASCII(tc.opts...))
+		})
+	}
+}
+
+func TestDoublyLinkedList_ASCII(t *testing.T) {
+	testCases := []struct {
+		name string
+		list func() *DoublyLinkedList[int]
+		want string
+	}{
+		{
+			name: "elements",
+			list: func() *DoublyLinkedList[int] {
+				return NewDoublyLinkedList(1, 2, 3)
+			},
+			want: "       head            tail\n" +
+				"       |               |\n" +
+				"       v               v\n" +
+				"nil <- [1] <-> [2] <-> [3] -> nil\n",
+		},
+		{
+			name: "broken prev",
+			list: func() *DoublyLinkedList[int] {
+				l := NewDoublyLinkedList(1, 2, 3)
+				l.tail.prev = l.head
+				l.head.prev = l.tail
+				return l
+			},
+			want: "      head            tail\n" +
+				"      |               |\n" +
+				"      v               v\n" +
+				"#2 x- [1] <-> [2] x-> [3] -> nil\n" +
+				"! prev of #0 (1) is #2 (3), want nil\n" +
+				"! prev of #2 (3) is #0 (1), want #1 (2)\n",
+		},
+		{
+			name: "prev outside the list",
+			list: func() *DoublyLinkedList[int] {
+				l := NewDoublyLinkedList(1, 2)
+				l.tail.prev = &DoublyNode[int]{val: 5}
+				return l
+			},
+			want: "       head    tail\n" +
+				"       |       |\n" +
+				"       v       v\n" +
+				"nil <- [1] x-> [2] -> nil\n" +
+				"! prev of #1 (2) is a node outside the list, want #0 (1)\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.list().ASCII())
+		})
+	}
+}
+
+func TestDoublyLinkedList_DOT(t *testing.T) {
+	l := NewDoublyLinkedList(1, 2)
+	l.tail.prev = l.tail
+	assert.Equal(t, `digraph "list" {
+	rankdir=LR;
+	node [shape=box];
+	n0 [label="1"];
+	n1 [label="2"];
+	n0 -> n1 [label="next"];
+	nil1 [label="nil", shape=plaintext];
+	n0 -> nil1 [label="prev"];
+	nil2 [label="nil", shape=plaintext];
+	n1 -> nil2 [label="next"];
+	n1 -> n1 [label="prev", color=red, fontcolor=red, style=dashed];
+	"ptr_head" [label="head", shape=plaintext];
+	"ptr_head" -> n0;
+	"ptr_tail" [label="tail", shape=plaintext];
+	"ptr_tail" -> n1;
+	label="prev of #1 (2) is #1 (2), want #0 (1)\l";
+	labelloc=b;
+	labeljust=l;
+	fontcolor=red;
+}
+`, l.DOT(visual.WithName[int]("list")))
+	assert.True(t, l.Graph().Broken())
+	assert.False(t, NewSinglyLinkedList(1).Graph().Broken())
+}
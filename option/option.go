@@ -0,0 +1,322 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+import "sync"
+
+// Options holds the configuration shared by the collections of the library.
+// A collection ignores the options that do not apply to it.
+type Options[T any] struct {
+	// Capacity is the number of elements to preallocate room for.
+	Capacity int
+	// MaxSize is the maximum number of elements of the collection, zero means unbounded.
+	MaxSize int
+	// Overflow decides what happens when an operation would exceed MaxSize.
+	Overflow OverflowPolicy
+	// OnEvict receives the elements dropped because of the overflow policy.
+	OnEvict func(elements []T)
+	// Equal reports whether two elements are equal.
+	Equal func(a, b T) bool
+	// Allocators allocate and release the nodes of node based collections, at most one per node type, see AllocatorOf.
+	Allocators []any
+	// Hooks are called after the collection has been changed.
+	Hooks Hooks[T]
+	// Shrink decides when a slice based collection releases its unused capacity.
+	Shrink ShrinkPolicy
+}
+
+// Option configures the Options of a collection.
+type Option[T any] func(o *Options[T])
+
+// Apply returns the Options configured by opts.
+func Apply[T any](opts ...Option[T]) Options[T] {
+	var o Options[T]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithCapacity preallocates room for capacity elements.
+func WithCapacity[T any](capacity int) Option[T] {
+	return func(o *Options[T]) {
+		o.Capacity = capacity
+	}
+}
+
+// WithMaxSize limits the collection to maxSize elements.
+// The operations that would exceed the limit are handled by the overflow policy, OverflowReject by default.
+func WithMaxSize[T any](maxSize int) Option[T] {
+	return func(o *Options[T]) {
+		o.MaxSize = maxSize
+	}
+}
+
+// WithOverflowPolicy sets the policy applied when an operation would exceed the max size.
+func WithOverflowPolicy[T any](policy OverflowPolicy) Option[T] {
+	return func(o *Options[T]) {
+		o.Overflow = policy
+	}
+}
+
+// WithOnEvict sets the function that receives the elements dropped because of the overflow policy:
+// the evicted elements for OverflowEvictOldest and OverflowEvictNewest,
+// the rejected elements for OverflowReject.
+func WithOnEvict[T any](onEvict func(elements []T)) Option[T] {
+	return func(o *Options[T]) {
+		o.OnEvict = onEvict
+	}
+}
+
+// WithEqual sets the function used to compare elements, e.g. by IndexOf and Contains.
+func WithEqual[T any](equal func(a, b T) bool) Option[T] {
+	return func(o *Options[T]) {
+		o.Equal = equal
+	}
+}
+
+// WithAllocator sets the allocator of the nodes of type N, e.g. linkedlist.SinglyNode[T].
+// A collection only uses the allocator of its node type and ignores the others.
+func WithAllocator[T, N any](allocator Allocator[N]) Option[T] {
+	return func(o *Options[T]) {
+		for i, a := range o.Allocators {
+			if _, ok := a.(Allocator[N]); ok {
+				o.Allocators[i] = allocator
+				return
+			}
+		}
+		o.Allocators = append(o.Allocators, allocator)
+	}
+}
+
+// AllocatorOf returns the allocator of the nodes of type N set in o, or nil if there is none.
+func AllocatorOf[N, T any](o Options[T]) Allocator[N] {
+	for _, a := range o.Allocators {
+		if allocator, ok := a.(Allocator[N]); ok {
+			return allocator
+		}
+	}
+	return nil
+}
+
+// WithHooks sets the hooks called after the collection has been changed.
+func WithHooks[T any](hooks Hooks[T]) Option[T] {
+	return func(o *Options[T]) {
+		o.Hooks = hooks
+	}
+}
+
+// WithShrinkPolicy releases the unused capacity of the collection once its size falls to ratio of its capacity,
+// keeping at least minCapacity, see ShrinkPolicy.
+func WithShrinkPolicy[T any](ratio float64, minCapacity int) Option[T] {
+	return func(o *Options[T]) {
+		o.Shrink = ShrinkPolicy{Ratio: ratio, MinCapacity: minCapacity}
+	}
+}
+
+// ShrinkPolicy decides when a slice based collection releases its unused capacity.
+// When the size falls to Ratio of the capacity, the elements are moved to a new slice
+// with a capacity of twice the size, or MinCapacity if it is larger.
+// A Ratio above 0.25 is treated as 0.25, which keeps the cost of the moves amortized O(1), zero disables shrinking.
+type ShrinkPolicy struct {
+	Ratio       float64
+	MinCapacity int
+}
+
+// maxShrinkRatio is the largest ratio honoured, a larger one would move the elements on almost every removal
+const maxShrinkRatio = 0.25
+
+// ShouldShrink checks whether a collection of the size and capacity should shrink.
+func (p ShrinkPolicy) ShouldShrink(size, capacity int) bool {
+	return p.Ratio > 0 && capacity > p.MinCapacity && p.Capacity(size) < capacity &&
+		float64(size) <= float64(capacity)*min(p.Ratio, maxShrinkRatio)
+}
+
+// Capacity returns the capacity of a collection of the size after shrinking.
+func (p ShrinkPolicy) Capacity(size int) int {
+	return max(2*size, p.MinCapacity)
+}
+
+// OverflowPolicy decides what happens when an operation would exceed the max size of a collection.
+type OverflowPolicy int
+
+const (
+	// OverflowReject rejects the whole operation.
+	OverflowReject OverflowPolicy = iota
+	// OverflowEvictOldest removes elements from the front of the collection to make room.
+	OverflowEvictOldest
+	// OverflowEvictNewest removes elements from the back of the collection to make room.
+	OverflowEvictNewest
+	// OverflowBlock waits until there is enough room.
+	// It is only supported by the concurrent collections, the others treat it as OverflowReject.
+	OverflowBlock
+)
+
+// Hooks are the callbacks invoked after a collection has been changed.
+// A nil callback is skipped.
+type Hooks[T any] struct {
+	// OnAdd is called for every element added at the index.
+	OnAdd func(index int, e T)
+	// OnRemove is called for every element removed from the index.
+	OnRemove func(index int, e T)
+	// OnSet is called when the element at the index is replaced by e.
+	OnSet func(index int, old, e T)
+	// OnClear is called when all the elements are removed.
+	OnClear func()
+	// OnReverse is called when the order of the elements is reversed.
+	OnReverse func()
+}
+
+// NotifyAdd calls OnAdd if it is set.
+func (h Hooks[T]) NotifyAdd(index int, e T) {
+	if h.OnAdd != nil {
+		h.OnAdd(index, e)
+	}
+}
+
+// NotifyRemove calls OnRemove if it is set.
+func (h Hooks[T]) NotifyRemove(index int, e T) {
+	if h.OnRemove != nil {
+		h.OnRemove(index, e)
+	}
+}
+
+// NotifySet calls OnSet if it is set.
+func (h Hooks[T]) NotifySet(index int, old, e T) {
+	if h.OnSet != nil {
+		h.OnSet(index, old, e)
+	}
+}
+
+// NotifyClear calls OnClear if it is set.
+func (h Hooks[T]) NotifyClear() {
+	if h.OnClear != nil {
+		h.OnClear()
+	}
+}
+
+// NotifyReverse calls OnReverse if it is set.
+func (h Hooks[T]) NotifyReverse() {
+	if h.OnReverse != nil {
+		h.OnReverse()
+	}
+}
+
+// Allocator allocates and releases the nodes of node based collections.
+type Allocator[N any] interface {
+	// Alloc returns a zeroed node.
+	Alloc() *N
+	// Free releases a node that is no longer used by the collection.
+	Free(n *N)
+}
+
+var _ Allocator[any] = (*PoolAllocator[any])(nil)
+
+// PoolAllocator is an Allocator that reuses the released nodes through a sync.Pool.
+type PoolAllocator[N any] struct {
+	pool sync.Pool
+}
+
+// NewPoolAllocator returns a new PoolAllocator.
+func NewPoolAllocator[N any]() *PoolAllocator[N] {
+	return &PoolAllocator[N]{
+		pool: sync.Pool{
+			New: func() any {
+				return new(N)
+			},
+		},
+	}
+}
+
+// Alloc returns a zeroed node.
+func (a *PoolAllocator[N]) Alloc() *N {
+	return a.pool.Get().(*N)
+}
+
+// Free zeroes the node and puts it back to the pool.
+func (a *PoolAllocator[N]) Free(n *N) {
+	var zero N
+	*n = zero
+	a.pool.Put(n)
+}
+
+// Observers is a set of Hooks notified of the changes of a collection.
+// The zero value is ready to use.
+type Observers[T any] struct {
+	hooks []*Hooks[T]
+}
+
+// NewObservers returns new Observers with the hooks already registered, unless none of them is set.
+func NewObservers[T any](hooks Hooks[T]) Observers[T] {
+	var o Observers[T]
+	if hooks.OnAdd != nil || hooks.OnRemove != nil || hooks.OnSet != nil || hooks.OnClear != nil || hooks.OnReverse != nil {
+		o.Observe(hooks)
+	}
+	return o
+}
+
+// Len returns the number of registered Hooks.
+func (o *Observers[T]) Len() int {
+	return len(o.hooks)
+}
+
+// Observe registers the hooks and returns a function unregistering them.
+func (o *Observers[T]) Observe(hooks Hooks[T]) (cancel func()) {
+	h := &hooks
+	o.hooks = append(o.hooks, h)
+	return func() {
+		for i, hh := range o.hooks {
+			if hh == h {
+				o.hooks = append(o.hooks[:i:i], o.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// NotifyAdd calls OnAdd of every registered Hooks.
+func (o *Observers[T]) NotifyAdd(index int, e T) {
+	for _, h := range o.hooks {
+		h.NotifyAdd(index, e)
+	}
+}
+
+// NotifyRemove calls OnRemove of every registered Hooks.
+func (o *Observers[T]) NotifyRemove(index int, e T) {
+	for _, h := range o.hooks {
+		h.NotifyRemove(index, e)
+	}
+}
+
+// NotifySet calls OnSet of every registered Hooks.
+func (o *Observers[T]) NotifySet(index int, old, e T) {
+	for _, h := range o.hooks {
+		h.NotifySet(index, old, e)
+	}
+}
+
+// NotifyClear calls OnClear of every registered Hooks.
+func (o *Observers[T]) NotifyClear() {
+	for _, h := range o.hooks {
+		h.NotifyClear()
+	}
+}
+
+// NotifyReverse calls OnReverse of every registered Hooks.
+func (o *Observers[T]) NotifyReverse() {
+	for _, h := range o.hooks {
+		h.NotifyReverse()
+	}
+}
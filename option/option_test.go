@@ -0,0 +1,153 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	var added []int
+	o := Apply[int](
+		WithCapacity[int](8),
+		WithMaxSize[int](16),
+		WithEqual[int](func(a, b int) bool { return a == b }),
+		WithAllocator[int](NewPoolAllocator[int]()),
+		WithHooks[int](Hooks[int]{
+			OnAdd: func(index int, e int) {
+				added = append(added, e)
+			},
+		}),
+	)
+	assert.Equal(t, 8, o.Capacity)
+	assert.Equal(t, 16, o.MaxSize)
+	assert.True(t, o.Equal(1, 1))
+	assert.IsType(t, &PoolAllocator[int]{}, AllocatorOf[int](o))
+	assert.Nil(t, AllocatorOf[string](o))
+	o.Hooks.NotifyAdd(0, 1)
+	o.Hooks.NotifyRemove(0, 1)
+	assert.Equal(t, []int{1}, added)
+}
+
+func TestAllocatorOf(t *testing.T) {
+	ints, strings := NewPoolAllocator[int](), NewPoolAllocator[string]()
+	o := Apply[int](
+		WithAllocator[int](NewPoolAllocator[int]()),
+		WithAllocator[int](strings),
+		// the last allocator of a node type replaces the previous one
+		WithAllocator[int](ints),
+	)
+	assert.Same(t, ints, AllocatorOf[int](o))
+	assert.Same(t, strings, AllocatorOf[string](o))
+	assert.Len(t, o.Allocators, 2)
+}
+
+func TestPoolAllocator(t *testing.T) {
+	a := NewPoolAllocator[int]()
+	n := a.Alloc()
+	assert.Equal(t, 0, *n)
+	*n = 1
+	a.Free(n)
+	assert.Equal(t, 0, *n)
+}
+
+func TestObservers(t *testing.T) {
+	var first, second []int
+	o := NewObservers[int](Hooks[int]{
+		OnAdd: func(index int, e int) {
+			first = append(first, e)
+		},
+	})
+	cancel := o.Observe(Hooks[int]{
+		OnAdd: func(index int, e int) {
+			second = append(second, e)
+		},
+	})
+	o.NotifyAdd(0, 1)
+	cancel()
+	o.NotifyAdd(1, 2)
+	o.NotifyClear()
+	assert.Equal(t, []int{1, 2}, first)
+	assert.Equal(t, []int{1}, second)
+}
+
+func TestShrinkPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     ShrinkPolicy
+		size       int
+		capacity   int
+		wantShrink bool
+		wantCap    int
+	}{
+		{
+			name:       "disabled",
+			policy:     ShrinkPolicy{},
+			size:       0,
+			capacity:   100,
+			wantShrink: false,
+			wantCap:    0,
+		},
+		{
+			name:       "above the ratio",
+			policy:     ShrinkPolicy{Ratio: 0.25},
+			size:       26,
+			capacity:   100,
+			wantShrink: false,
+			wantCap:    52,
+		},
+		{
+			name:       "at the ratio",
+			policy:     ShrinkPolicy{Ratio: 0.25},
+			size:       25,
+			capacity:   100,
+			wantShrink: true,
+			wantCap:    50,
+		},
+		{
+			name:       "large ratio",
+			policy:     ShrinkPolicy{Ratio: 0.9},
+			size:       26,
+			capacity:   100,
+			wantShrink: false,
+			wantCap:    52,
+		},
+		{
+			name:       "large ratio capped",
+			policy:     ShrinkPolicy{Ratio: 2},
+			size:       25,
+			capacity:   100,
+			wantShrink: true,
+			wantCap:    50,
+		},
+		{
+			name:       "min capacity",
+			policy:     ShrinkPolicy{Ratio: 0.25, MinCapacity: 100},
+			size:       0,
+			capacity:   100,
+			wantShrink: false,
+			wantCap:    100,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantShrink, tc.policy.ShouldShrink(tc.size, tc.capacity))
+			assert.Equal(t, tc.wantCap, tc.policy.Capacity(tc.size))
+		})
+	}
+	assert.Equal(t, ShrinkPolicy{Ratio: 0.5, MinCapacity: 2}, Apply(WithShrinkPolicy[int](0.5, 2)).Shrink)
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+var _ Queue[any] = (*LockFreeQueue[any])(nil)
+
+// lockFreeNode is a node of a LockFreeQueue
+type lockFreeNode[T any] struct {
+	value T
+	next  atomic.Pointer[lockFreeNode[T]]
+}
+
+// LockFreeQueue is an unbounded lock-free FIFO queue, the Michael-Scott queue.
+// The head is a dummy node whose successor is the front of the queue,
+// the tail is the last node or lags one node behind, in which case any operation moves it forward.
+type LockFreeQueue[T any] struct {
+	head atomic.Pointer[lockFreeNode[T]]
+	_    cacheLinePad
+	tail atomic.Pointer[lockFreeNode[T]]
+	_    cacheLinePad
+	size atomic.Int64
+
+	notEmpty signal
+}
+
+// NewLockFreeQueue returns a new empty queue
+func NewLockFreeQueue[T any]() *LockFreeQueue[T] {
+	q := &LockFreeQueue[T]{}
+	dummy := &lockFreeNode[T]{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+// TryEnqueue adds an element to the back of the queue, it always succeeds since the queue is unbounded
+func (q *LockFreeQueue[T]) TryEnqueue(e T) bool {
+	n := &lockFreeNode[T]{value: e}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// the tail lags behind, help moving it
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, n) {
+			q.tail.CompareAndSwap(tail, n)
+			break
+		}
+	}
+	q.size.Add(1)
+	q.notEmpty.broadcast()
+	return true
+}
+
+// TryDequeue removes the element at the front of the queue and returns that element
+// If the queue is empty, b return false
+func (q *LockFreeQueue[T]) TryDequeue() (t T, b bool) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if next == nil {
+			return
+		}
+		if head == tail {
+			// the tail lags behind, help moving it
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		// the value is read before the node becomes the dummy, which another dequeue may then drop
+		t = next.value
+		if q.head.CompareAndSwap(head, next) {
+			q.size.Add(-1)
+			return t, true
+		}
+	}
+}
+
+// Enqueue adds an element to the back of the queue, it never blocks since the queue is unbounded
+// and returns nil
+func (q *LockFreeQueue[T]) Enqueue(_ context.Context, e T) error {
+	q.TryEnqueue(e)
+	return nil
+}
+
+// Dequeue removes the element at the front of the queue and returns that element,
+// waiting for one until ctx is done
+// It returns the error of ctx if no element could be removed
+func (q *LockFreeQueue[T]) Dequeue(ctx context.Context) (t T, err error) {
+	err = await(ctx, &q.notEmpty, func() (ok bool) {
+		t, ok = q.TryDequeue()
+		return ok
+	})
+	return t, err
+}
+
+// Size returns the number of elements of the queue, it may be off while operations are in progress
+func (q *LockFreeQueue[T]) Size() int {
+	return max(int(q.size.Load()), 0)
+}
+
+// IsEmpty checks whether the queue is empty
+func (q *LockFreeQueue[T]) IsEmpty() bool {
+	return q.head.Load().next.Load() == nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeQueue_Enqueue(t *testing.T) {
+	q := NewLockFreeQueue[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// the queue is unbounded, so enqueuing never waits
+	assert.NoError(t, q.Enqueue(ctx, 1))
+	assert.True(t, q.TryEnqueue(2))
+	v, err := q.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	// the dequeued node becomes the dummy head
+	assert.Equal(t, 1, q.head.Load().value)
+	assert.Same(t, q.head.Load().next.Load(), q.tail.Load())
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package queue implements lock-free FIFO queues safe for concurrent use.
+package queue
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Queue is a FIFO queue safe for concurrent use.
+type Queue[T any] interface {
+	// TryEnqueue adds an element to the back of the queue without blocking
+	// If the queue is full, b return false
+	TryEnqueue(e T) bool
+	// TryDequeue removes the element at the front of the queue and returns that element without blocking
+	// If the queue is empty, b return false
+	TryDequeue() (T, bool)
+	// Enqueue adds an element to the back of the queue, waiting for room until ctx is done
+	// It returns the error of ctx if the element could not be added
+	Enqueue(ctx context.Context, e T) error
+	// Dequeue removes the element at the front of the queue and returns that element,
+	// waiting for one until ctx is done
+	// It returns the error of ctx if no element could be removed
+	Dequeue(ctx context.Context) (T, error)
+	// Size returns the number of elements of the queue, it may be off while operations are in progress
+	Size() int
+	// IsEmpty checks whether the queue is empty, it may be off while operations are in progress
+	IsEmpty() bool
+}
+
+// cacheLinePad keeps the fields written by different goroutines on different cache lines
+type cacheLinePad [64]byte
+
+// signal wakes up the goroutines waiting for a queue to change.
+// It only takes a lock when some goroutine is waiting, so that the queue stays lock-free otherwise.
+type signal struct {
+	waiters atomic.Int32
+	lock    sync.Mutex
+	ch      chan struct{}
+}
+
+// wait registers a waiter and returns the channel closed by the next broadcast,
+// the waiter must then check the queue again before waiting on the channel, and call done when it stops waiting
+func (s *signal) wait() <-chan struct{} {
+	s.waiters.Add(1)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+func (s *signal) done() {
+	s.waiters.Add(-1)
+}
+
+// broadcast wakes up the waiters, if any
+func (s *signal) broadcast() {
+	if s.waiters.Load() == 0 {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+}
+
+// await calls try until it succeeds, waiting for a broadcast of s between the attempts, or until ctx is done
+func await(ctx context.Context, s *signal, try func() bool) error {
+	for {
+		if try() {
+			return nil
+		}
+		ch := s.wait()
+		// a broadcast may have happened since the first attempt, before the waiter was registered
+		if try() {
+			s.done()
+			return nil
+		}
+		select {
+		case <-ch:
+			s.done()
+		case <-ctx.Done():
+			s.done()
+			return ctx.Err()
+		}
+	}
+}
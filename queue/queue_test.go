@@ -0,0 +1,140 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newQueues returns the queues to test, with room for at least capacity elements
+func newQueues(capacity int) map[string]Queue[int] {
+	return map[string]Queue[int]{
+		"LockFreeQueue": NewLockFreeQueue[int](),
+		"RingQueue":     NewRingQueue[int](capacity),
+	}
+}
+
+func TestQueue(t *testing.T) {
+	for name, q := range newQueues(8) {
+		t.Run(name, func(t *testing.T) {
+			_, ok := q.TryDequeue()
+			assert.False(t, ok)
+			assert.True(t, q.IsEmpty())
+			for i := 0; i < 5; i++ {
+				assert.True(t, q.TryEnqueue(i))
+			}
+			assert.Equal(t, 5, q.Size())
+			assert.False(t, q.IsEmpty())
+			for i := 0; i < 5; i++ {
+				v, ok := q.TryDequeue()
+				assert.True(t, ok)
+				assert.Equal(t, i, v)
+			}
+			assert.True(t, q.IsEmpty())
+			assert.Equal(t, 0, q.Size())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			_, err := q.Dequeue(ctx)
+			assert.Equal(t, context.DeadlineExceeded, err)
+		})
+	}
+}
+
+func TestQueue_BlockingDequeue(t *testing.T) {
+	for name, q := range newQueues(8) {
+		t.Run(name, func(t *testing.T) {
+			dequeued := make(chan int)
+			go func() {
+				v, err := q.Dequeue(context.Background())
+				assert.NoError(t, err)
+				dequeued <- v
+			}()
+			select {
+			case <-dequeued:
+				t.Fatal("dequeue should block while the queue is empty")
+			case <-time.After(20 * time.Millisecond):
+			}
+			assert.NoError(t, q.Enqueue(context.Background(), 42))
+			assert.Equal(t, 42, <-dequeued)
+		})
+	}
+}
+
+func TestQueue_Concurrent(t *testing.T) {
+	const producers, consumers, n = 4, 4, 5000
+	for name, q := range newQueues(16) {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for p := 0; p < producers; p++ {
+				wg.Add(1)
+				go func(p int) {
+					defer wg.Done()
+					for j := 0; j < n; j++ {
+						assert.NoError(t, q.Enqueue(context.Background(), p*n+j))
+					}
+				}(p)
+			}
+			received := make([][]int, consumers)
+			for c := 0; c < consumers; c++ {
+				wg.Add(1)
+				go func(c int) {
+					defer wg.Done()
+					for j := 0; j < n; j++ {
+						v, err := q.Dequeue(context.Background())
+						assert.NoError(t, err)
+						received[c] = append(received[c], v)
+					}
+				}(c)
+			}
+			wg.Wait()
+			assert.True(t, q.IsEmpty())
+
+			// every element is received exactly once, and the elements of a producer in order
+			seen := make([]bool, producers*n)
+			for _, values := range received {
+				last := make([]int, producers)
+				for i := range last {
+					last[i] = -1
+				}
+				for _, v := range values {
+					assert.False(t, seen[v])
+					seen[v] = true
+					p := v / n
+					assert.Greater(t, v, last[p])
+					last[p] = v
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkQueue(b *testing.B) {
+	for name, q := range newQueues(1024) {
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					q.TryEnqueue(1)
+					q.TryDequeue()
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+var _ Queue[any] = (*RingQueue[any])(nil)
+
+// cell is a slot of a RingQueue, its sequence tells for which position it is ready:
+// pos when it is free for the enqueue at pos, pos+1 when it holds the element enqueued at pos
+type cell[T any] struct {
+	sequence atomic.Uint64
+	value    T
+}
+
+// RingQueue is a bounded multi-producer multi-consumer FIFO queue, Dmitry Vyukov's ring of sequenced cells.
+// A producer or a consumer claims a position with a single CAS, then owns its cell until it bumps the sequence.
+type RingQueue[T any] struct {
+	cells []cell[T]
+	mask  uint64
+	_     cacheLinePad
+	// enqueuePos is the position of the next element to enqueue
+	enqueuePos atomic.Uint64
+	_          cacheLinePad
+	// dequeuePos is the position of the next element to dequeue
+	dequeuePos atomic.Uint64
+	_          cacheLinePad
+
+	notEmpty signal
+	notFull  signal
+}
+
+// NewRingQueue returns a new queue holding up to capacity elements, rounded up to a power of two of at least 2.
+func NewRingQueue[T any](capacity int) *RingQueue[T] {
+	size := uint64(2)
+	for size < uint64(max(capacity, 0)) {
+		size <<= 1
+	}
+	q := &RingQueue[T]{
+		cells: make([]cell[T], size),
+		mask:  size - 1,
+	}
+	for i := range q.cells {
+		q.cells[i].sequence.Store(uint64(i))
+	}
+	return q
+}
+
+// TryEnqueue adds an element to the back of the queue
+// If the queue is full, b return false
+func (q *RingQueue[T]) TryEnqueue(e T) bool {
+	pos := q.enqueuePos.Load()
+	for {
+		c := &q.cells[pos&q.mask]
+		switch dif := int64(c.sequence.Load() - pos); {
+		case dif == 0:
+			if q.enqueuePos.CompareAndSwap(pos, pos+1) {
+				c.value = e
+				c.sequence.Store(pos + 1)
+				q.notEmpty.broadcast()
+				return true
+			}
+			pos = q.enqueuePos.Load()
+		case dif < 0:
+			// the cell still holds the element enqueued a lap ago
+			return false
+		default:
+			// another producer took the position
+			pos = q.enqueuePos.Load()
+		}
+	}
+}
+
+// TryDequeue removes the element at the front of the queue and returns that element
+// If the queue is empty, b return false
+func (q *RingQueue[T]) TryDequeue() (t T, b bool) {
+	pos := q.dequeuePos.Load()
+	for {
+		c := &q.cells[pos&q.mask]
+		switch dif := int64(c.sequence.Load() - (pos + 1)); {
+		case dif == 0:
+			if q.dequeuePos.CompareAndSwap(pos, pos+1) {
+				t = c.value
+				var zero T
+				c.value = zero
+				// the cell is free for the enqueue a lap later
+				c.sequence.Store(pos + q.mask + 1)
+				q.notFull.broadcast()
+				return t, true
+			}
+			pos = q.dequeuePos.Load()
+		case dif < 0:
+			// nothing was enqueued at the position yet
+			return
+		default:
+			// another consumer took the position
+			pos = q.dequeuePos.Load()
+		}
+	}
+}
+
+// Enqueue adds an element to the back of the queue, waiting for room until ctx is done
+// It returns the error of ctx if the element could not be added
+func (q *RingQueue[T]) Enqueue(ctx context.Context, e T) error {
+	return await(ctx, &q.notFull, func() bool {
+		return q.TryEnqueue(e)
+	})
+}
+
+// Dequeue removes the element at the front of the queue and returns that element,
+// waiting for one until ctx is done
+// It returns the error of ctx if no element could be removed
+func (q *RingQueue[T]) Dequeue(ctx context.Context) (t T, err error) {
+	err = await(ctx, &q.notEmpty, func() (ok bool) {
+		t, ok = q.TryDequeue()
+		return ok
+	})
+	return t, err
+}
+
+// Cap returns the maximum number of elements of the queue
+func (q *RingQueue[T]) Cap() int {
+	return len(q.cells)
+}
+
+// Size returns the number of elements of the queue, it may be off while operations are in progress
+func (q *RingQueue[T]) Size() int {
+	dequeued := q.dequeuePos.Load()
+	enqueued := q.enqueuePos.Load()
+	if enqueued < dequeued {
+		return 0
+	}
+	return min(int(enqueued-dequeued), len(q.cells))
+}
+
+// IsEmpty checks whether the queue is empty, it may be off while operations are in progress
+func (q *RingQueue[T]) IsEmpty() bool {
+	return q.Size() == 0
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRingQueue(t *testing.T) {
+	assert.Equal(t, 2, NewRingQueue[int](0).Cap())
+	assert.Equal(t, 2, NewRingQueue[int](-1).Cap())
+	assert.Equal(t, 8, NewRingQueue[int](5).Cap())
+	assert.Equal(t, 8, NewRingQueue[int](8).Cap())
+}
+
+func TestRingQueue_Full(t *testing.T) {
+	q := NewRingQueue[*int](2)
+	// goes around the ring several times
+	for lap := 0; lap < 3; lap++ {
+		assert.True(t, q.TryEnqueue(new(int)))
+		assert.True(t, q.TryEnqueue(new(int)))
+		assert.False(t, q.TryEnqueue(new(int)))
+		assert.Equal(t, 2, q.Size())
+		_, ok := q.TryDequeue()
+		assert.True(t, ok)
+		// the dequeued cell is cleared
+		assert.Nil(t, q.cells[0].value)
+		_, ok = q.TryDequeue()
+		assert.True(t, ok)
+		_, ok = q.TryDequeue()
+		assert.False(t, ok)
+	}
+}
+
+func TestRingQueue_BlockingEnqueue(t *testing.T) {
+	q := NewRingQueue[int](2)
+	q.TryEnqueue(1)
+	q.TryEnqueue(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, q.Enqueue(ctx, 3))
+
+	enqueued := make(chan struct{})
+	go func() {
+		assert.NoError(t, q.Enqueue(context.Background(), 3))
+		close(enqueued)
+	}()
+	select {
+	case <-enqueued:
+		t.Fatal("enqueue should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	v, ok := q.TryDequeue()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	<-enqueued
+	v, _ = q.TryDequeue()
+	assert.Equal(t, 2, v)
+	v, _ = q.TryDequeue()
+	assert.Equal(t, 3, v)
+}
@@ -0,0 +1,474 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rope implements a persistent rope, a balanced tree of byte or rune chunks
+// supporting O(log n) concatenation, split, insertion, deletion and indexing.
+package rope
+
+import (
+	"io"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Char is the element type of a Rope.
+type Char interface {
+	~byte | ~rune
+}
+
+// maxLeaf is the maximum number of elements of a leaf
+const maxLeaf = 512
+
+// node is a leaf holding a chunk of elements or an inner node concatenating two subtrees.
+// Nodes are never changed once built, so they are shared between ropes.
+type node[T Char] struct {
+	data        []T
+	left, right *node[T]
+	length      int
+	newlines    int
+	height      int
+}
+
+// Rope is an immutable sequence of bytes or runes.
+// The operations changing a Rope return a new Rope sharing most of its nodes with the original.
+// The zero value is an empty rope.
+type Rope[T Char] struct {
+	root *node[T]
+}
+
+// New returns a new rope containing a copy of the elements.
+func New[T Char](elements []T) *Rope[T] {
+	return &Rope[T]{root: build(elements)}
+}
+
+// NewString returns a new rope containing the string,
+// as bytes if T is a byte type and as runes otherwise.
+func NewString[T Char](s string) *Rope[T] {
+	var zero T
+	if unsafe.Sizeof(zero) == 1 {
+		return New(toChars[T]([]byte(s)))
+	}
+	return New(toChars[T]([]rune(s)))
+}
+
+// Concat returns the concatenation of the ropes.
+func Concat[T Char](ropes ...*Rope[T]) *Rope[T] {
+	var root *node[T]
+	for _, r := range ropes {
+		root = join(root, r.root)
+	}
+	return &Rope[T]{root: root}
+}
+
+// Len returns the number of elements of the rope.
+func (r *Rope[T]) Len() int {
+	return r.root.len()
+}
+
+// At returns the element at the index.
+// If the index is invalid, b return false
+func (r *Rope[T]) At(index int) (t T, b bool) {
+	if index < 0 || index >= r.Len() {
+		return
+	}
+	n := r.root
+	for n.data == nil {
+		if index < n.left.len() {
+			n = n.left
+		} else {
+			index -= n.left.len()
+			n = n.right
+		}
+	}
+	return n.data[index], true
+}
+
+// Concat returns the concatenation of the rope and other.
+func (r *Rope[T]) Concat(other *Rope[T]) *Rope[T] {
+	return &Rope[T]{root: join(r.root, other.root)}
+}
+
+// Split splits the rope into the elements before the index and the others.
+// The index is clamped to [0, Len()].
+func (r *Rope[T]) Split(index int) (*Rope[T], *Rope[T]) {
+	left, right := split(r.root, r.clamp(index))
+	return &Rope[T]{root: left}, &Rope[T]{root: right}
+}
+
+// Insert returns a rope with the elements inserted before the index.
+// The index is clamped to [0, Len()].
+func (r *Rope[T]) Insert(index int, elements []T) *Rope[T] {
+	left, right := split(r.root, r.clamp(index))
+	return &Rope[T]{root: join(join(left, build(elements)), right)}
+}
+
+// Delete returns a rope without the elements in [from, to).
+// The indexes are clamped to [0, Len()].
+func (r *Rope[T]) Delete(from, to int) *Rope[T] {
+	from, to = r.clamp(from), r.clamp(to)
+	if from >= to {
+		return r
+	}
+	left, rest := split(r.root, from)
+	_, right := split(rest, to-from)
+	return &Rope[T]{root: join(left, right)}
+}
+
+// Slice returns a rope of the elements in [from, to).
+// The indexes are clamped to [0, Len()].
+func (r *Rope[T]) Slice(from, to int) *Rope[T] {
+	from, to = r.clamp(from), r.clamp(to)
+	if from >= to {
+		return &Rope[T]{}
+	}
+	_, rest := split(r.root, from)
+	middle, _ := split(rest, to-from)
+	return &Rope[T]{root: middle}
+}
+
+// Values returns a slice containing all the elements of the rope.
+func (r *Rope[T]) Values() []T {
+	elements := make([]T, 0, r.Len())
+	for it := r.leaves(); ; {
+		leaf := it.next()
+		if leaf == nil {
+			return elements
+		}
+		elements = append(elements, leaf.data...)
+	}
+}
+
+// String returns the elements of the rope as a string, runes are encoded in UTF-8.
+func (r *Rope[T]) String() string {
+	b, _ := io.ReadAll(r.Reader())
+	return string(b)
+}
+
+// LineCount returns the number of lines of the rope, that is the number of '\n' plus one.
+func (r *Rope[T]) LineCount() int {
+	return r.root.lines() + 1
+}
+
+// LineStart returns the index of the first element of the line, lines are numbered from 0.
+// If the line does not exist, b return false
+func (r *Rope[T]) LineStart(line int) (index int, b bool) {
+	if line < 0 || line >= r.LineCount() {
+		return
+	}
+	if line == 0 {
+		return 0, true
+	}
+	// find the (line-1)-th newline, the line starts after it
+	n, k := r.root, line-1
+	for n.data == nil {
+		if k < n.left.lines() {
+			n = n.left
+		} else {
+			k -= n.left.lines()
+			index += n.left.len()
+			n = n.right
+		}
+	}
+	for i, c := range n.data {
+		if c == '\n' {
+			if k == 0 {
+				return index + i + 1, true
+			}
+			k--
+		}
+	}
+	return
+}
+
+// LineOf returns the line of the element at the index.
+// The index is clamped to [0, Len()].
+func (r *Rope[T]) LineOf(index int) int {
+	index = r.clamp(index)
+	line := 0
+	n := r.root
+	for n != nil && n.data == nil {
+		if index < n.left.len() {
+			n = n.left
+		} else {
+			index -= n.left.len()
+			line += n.left.lines()
+			n = n.right
+		}
+	}
+	if n != nil {
+		line += countNewlines(n.data[:index])
+	}
+	return line
+}
+
+// Iterator returns an iterator over the elements of the rope from the index.
+// The index is clamped to [0, Len()].
+func (r *Rope[T]) Iterator(from int) *Iterator[T] {
+	from = r.clamp(from)
+	it := &Iterator[T]{pos: from}
+	// keep the right subtrees on the path to the index to visit them afterwards
+	n := r.root
+	for n != nil && n.data == nil {
+		if from < n.left.len() {
+			it.leaves.stack = append(it.leaves.stack, n.right)
+			n = n.left
+		} else {
+			from -= n.left.len()
+			n = n.right
+		}
+	}
+	if n != nil {
+		it.data = n.data[from:]
+	}
+	return it
+}
+
+// Reader returns a reader of the elements of the rope, runes are encoded in UTF-8.
+func (r *Rope[T]) Reader() io.Reader {
+	return &reader[T]{it: r.Iterator(0)}
+}
+
+func (r *Rope[T]) clamp(index int) int {
+	return min(max(index, 0), r.Len())
+}
+
+// Iterator iterates over the elements of a rope.
+type Iterator[T Char] struct {
+	leaves leafIterator[T]
+	data   []T
+	pos    int
+}
+
+// Next returns the next element.
+// If there is no more element, b return false
+func (it *Iterator[T]) Next() (t T, b bool) {
+	for len(it.data) == 0 {
+		leaf := it.leaves.next()
+		if leaf == nil {
+			return
+		}
+		it.data = leaf.data
+	}
+	t = it.data[0]
+	it.data = it.data[1:]
+	it.pos++
+	return t, true
+}
+
+// Index returns the index of the element returned by the next call to Next.
+func (it *Iterator[T]) Index() int {
+	return it.pos
+}
+
+// nextChunk returns the remaining elements of the current leaf, or nil if there is none
+func (it *Iterator[T]) nextChunk() []T {
+	for len(it.data) == 0 {
+		leaf := it.leaves.next()
+		if leaf == nil {
+			return nil
+		}
+		it.data = leaf.data
+	}
+	chunk := it.data
+	it.data = nil
+	it.pos += len(chunk)
+	return chunk
+}
+
+// leafIterator iterates over the leaves of a tree in order
+type leafIterator[T Char] struct {
+	stack []*node[T]
+}
+
+func (r *Rope[T]) leaves() *leafIterator[T] {
+	it := &leafIterator[T]{}
+	if r.root != nil {
+		it.stack = append(it.stack, r.root)
+	}
+	return it
+}
+
+func (it *leafIterator[T]) next() *node[T] {
+	for len(it.stack) > 0 {
+		n := it.stack[len(it.stack)-1]
+		it.stack = it.stack[:len(it.stack)-1]
+		if n.data != nil {
+			return n
+		}
+		it.stack = append(it.stack, n.right, n.left)
+	}
+	return nil
+}
+
+// reader reads the elements of a rope as bytes
+type reader[T Char] struct {
+	it      *Iterator[T]
+	pending []byte
+}
+
+func (r *reader[T]) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(r.pending) == 0 {
+		chunk := r.it.nextChunk()
+		if chunk == nil {
+			return 0, io.EOF
+		}
+		r.pending = encode(r.pending[:0], chunk)
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// encode appends the elements to b, runes are encoded in UTF-8
+func encode[T Char](b []byte, elements []T) []byte {
+	var zero T
+	if unsafe.Sizeof(zero) == 1 {
+		for _, c := range elements {
+			b = append(b, byte(c))
+		}
+		return b
+	}
+	for _, c := range elements {
+		b = utf8.AppendRune(b, rune(c))
+	}
+	return b
+}
+
+func toChars[T Char, S byte | rune](s []S) []T {
+	elements := make([]T, len(s))
+	for i, c := range s {
+		elements[i] = T(c)
+	}
+	return elements
+}
+
+func countNewlines[T Char](elements []T) int {
+	count := 0
+	for _, c := range elements {
+		if c == '\n' {
+			count++
+		}
+	}
+	return count
+}
+
+func (n *node[T]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.length
+}
+
+func (n *node[T]) lines() int {
+	if n == nil {
+		return 0
+	}
+	return n.newlines
+}
+
+func (n *node[T]) depth() int {
+	if n == nil {
+		return -1
+	}
+	return n.height
+}
+
+func newLeaf[T Char](data []T) *node[T] {
+	return &node[T]{data: data, length: len(data), newlines: countNewlines(data)}
+}
+
+func newInner[T Char](left, right *node[T]) *node[T] {
+	return &node[T]{
+		left:     left,
+		right:    right,
+		length:   left.len() + right.len(),
+		newlines: left.lines() + right.lines(),
+		height:   max(left.depth(), right.depth()) + 1,
+	}
+}
+
+// build builds a balanced tree of a copy of the elements
+func build[T Char](elements []T) *node[T] {
+	if len(elements) == 0 {
+		return nil
+	}
+	if len(elements) <= maxLeaf {
+		return newLeaf(append(make([]T, 0, len(elements)), elements...))
+	}
+	// split on a multiple of maxLeaf to keep the leaves full
+	mid := (len(elements) + maxLeaf - 1) / maxLeaf / 2 * maxLeaf
+	return newInner(build(elements[:mid]), build(elements[mid:]))
+}
+
+// join concatenates two balanced trees into a balanced tree
+func join[T Char](left, right *node[T]) *node[T] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.data != nil && right.data != nil && left.length+right.length <= maxLeaf:
+		data := make([]T, 0, left.length+right.length)
+		return newLeaf(append(append(data, left.data...), right.data...))
+	case left.height > right.height+1:
+		return rebalance(newInner(left.left, join(left.right, right)))
+	case right.height > left.height+1:
+		return rebalance(newInner(join(left, right.left), right.right))
+	default:
+		return newInner(left, right)
+	}
+}
+
+// rebalance restores the AVL property of a node whose children heights differ by at most 2
+func rebalance[T Char](n *node[T]) *node[T] {
+	left, right := n.left, n.right
+	switch {
+	case left.depth() > right.depth()+1:
+		if left.left.depth() >= left.right.depth() {
+			return newInner(left.left, newInner(left.right, right))
+		}
+		return newInner(newInner(left.left, left.right.left), newInner(left.right.right, right))
+	case right.depth() > left.depth()+1:
+		if right.right.depth() >= right.left.depth() {
+			return newInner(newInner(left, right.left), right.right)
+		}
+		return newInner(newInner(left, right.left.left), newInner(right.left.right, right.right))
+	default:
+		return n
+	}
+}
+
+// split splits the tree into the first k elements and the others
+func split[T Char](n *node[T], k int) (*node[T], *node[T]) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case k <= 0:
+		return nil, n
+	case k >= n.length:
+		return n, nil
+	case n.data != nil:
+		// the full slice expression keeps the shared data from being appended to
+		return newLeaf(n.data[:k:k]), newLeaf(n.data[k:])
+	case k <= n.left.len():
+		left, right := split(n.left, k)
+		return left, join(right, n.right)
+	default:
+		left, right := split(n.right, k-n.left.len())
+		return join(n.left, left), right
+	}
+}
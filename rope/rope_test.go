@@ -0,0 +1,163 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rope
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkTree checks the cached fields and the balance of the tree
+func checkTree[T Char](t *testing.T, n *node[T]) {
+	if n == nil {
+		return
+	}
+	if n.data != nil {
+		require.NotEmpty(t, n.data)
+		require.LessOrEqual(t, len(n.data), maxLeaf)
+		require.Equal(t, len(n.data), n.length)
+		require.Equal(t, countNewlines(n.data), n.newlines)
+		require.Equal(t, 0, n.height)
+		return
+	}
+	checkTree(t, n.left)
+	checkTree(t, n.right)
+	require.Equal(t, n.left.len()+n.right.len(), n.length)
+	require.Equal(t, n.left.lines()+n.right.lines(), n.newlines)
+	require.Equal(t, max(n.left.depth(), n.right.depth())+1, n.height)
+	require.LessOrEqual(t, abs(n.left.depth()-n.right.depth()), 1)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func TestRope_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomBytes := func(n int) []byte {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "ab\n"[r.Intn(3)]
+		}
+		return b
+	}
+	want := randomBytes(3000)
+	rope := New(want)
+	for i := 0; i < 2000; i++ {
+		switch r.Intn(4) {
+		case 0:
+			idx, elements := r.Intn(len(want)+1), randomBytes(r.Intn(1200))
+			rope = rope.Insert(idx, elements)
+			want = append(want[:idx:idx], append(elements, want[idx:]...)...)
+		case 1:
+			from := r.Intn(len(want) + 1)
+			to := from + r.Intn(len(want)-from+1)
+			rope = rope.Delete(from, to)
+			want = append(want[:from:from], want[to:]...)
+		case 2:
+			idx := r.Intn(len(want) + 1)
+			left, right := rope.Split(idx)
+			assert.Equal(t, idx, left.Len())
+			rope = right.Concat(left)
+			want = append(want[idx:len(want):len(want)], want[:idx]...)
+		default:
+			if len(want) > 0 {
+				idx := r.Intn(len(want))
+				got, ok := rope.At(idx)
+				assert.True(t, ok)
+				assert.Equal(t, want[idx], got)
+				assert.Equal(t, strings.Count(string(want[:idx]), "\n"), rope.LineOf(idx))
+			}
+		}
+		checkTree(t, rope.root)
+		require.Equal(t, len(want), rope.Len())
+	}
+	assert.Equal(t, want, rope.Values())
+	assert.Equal(t, string(want), rope.String())
+}
+
+func TestRope_Lines(t *testing.T) {
+	text := strings.Repeat("hello\nworld\n", 200)
+	r := NewString[byte](text)
+	lines := strings.Split(text, "\n")
+	assert.Equal(t, len(lines), r.LineCount())
+	start := 0
+	for i, line := range lines {
+		got, ok := r.LineStart(i)
+		assert.True(t, ok)
+		assert.Equal(t, start, got)
+		assert.Equal(t, i, r.LineOf(start))
+		start += len(line) + 1
+	}
+	_, ok := r.LineStart(len(lines))
+	assert.False(t, ok)
+	_, ok = r.LineStart(-1)
+	assert.False(t, ok)
+}
+
+func TestRope_Runes(t *testing.T) {
+	r := NewString[rune]("héllo, 世界")
+	assert.Equal(t, 9, r.Len())
+	c, ok := r.At(8)
+	assert.True(t, ok)
+	assert.Equal(t, '界', c)
+	r = r.Insert(5, []rune(" wörld"))
+	assert.Equal(t, "héllo wörld, 世界", r.String())
+	b, err := io.ReadAll(r.Slice(6, 11).Reader())
+	assert.NoError(t, err)
+	assert.Equal(t, "wörld", string(b))
+	_, ok = r.At(r.Len())
+	assert.False(t, ok)
+}
+
+func TestRope_Iterator(t *testing.T) {
+	text := strings.Repeat("0123456789", 300)
+	r := NewString[byte](text)
+	it := r.Iterator(1234)
+	var got []byte
+	for {
+		assert.Equal(t, 1234+len(got), it.Index())
+		c, ok := it.Next()
+		if !ok {
+			break
+		}
+		got = append(got, c)
+	}
+	assert.Equal(t, text[1234:], string(got))
+}
+
+func TestRope_Immutable(t *testing.T) {
+	original := NewString[byte]("hello world")
+	changed := original.Insert(5, []byte(",")).Delete(0, 1)
+	assert.Equal(t, "hello world", original.String())
+	assert.Equal(t, "ello, world", changed.String())
+	assert.Equal(t, "", (&Rope[byte]{}).String())
+	assert.Equal(t, "ab", Concat(NewString[byte]("a"), &Rope[byte]{}, NewString[byte]("b")).String())
+}
+
+func BenchmarkRope_Insert(b *testing.B) {
+	r := NewString[byte](strings.Repeat("x", 1<<20))
+	for i := 0; i < b.N; i++ {
+		r = r.Insert(i%r.Len(), []byte("y"))
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spill
+
+import (
+	"github.com/chenmingyong0423/algorithms/codec"
+	"github.com/chenmingyong0423/algorithms/errs"
+)
+
+// Queue is a FIFO queue keeping its front and back elements in memory and spilling the ones in between to disk,
+// a segment at a time. When the elements in memory exceed the memory budget, the oldest segment of the back
+// is written to a file, which is read back once the elements before it have been dequeued.
+// It must be closed to remove its files. It is not safe for concurrent use.
+type Queue[T any] struct {
+	store *segmentStore[T]
+	// head holds the front elements, the first one first
+	head []T
+	// spilled holds the segments on disk, between head and tail, the oldest first
+	spilled      []segmentFile
+	spilledCount int
+	// tail holds the back elements, the last one last
+	tail []T
+
+	config config
+	err    error
+	closed bool
+}
+
+// NewQueue returns a new queue spilling its elements encoded by codec, configured by opts.
+// It returns an error if the directory of the segments cannot be created.
+func NewQueue[T any](codec codec.Codec[T], opts ...Option) (*Queue[T], error) {
+	c := newConfig(opts)
+	store, err := newSegmentStore[T](c.dir, codec)
+	if err != nil {
+		return nil, err
+	}
+	return &Queue[T]{store: store, config: c}, nil
+}
+
+// Enqueue adds an element to the back of the queue
+// If spilling fails, the elements stay in memory and the error is reported by Err
+func (q *Queue[T]) Enqueue(e T) {
+	if q.closed {
+		q.err = ErrClosed
+		return
+	}
+	q.tail = append(q.tail, e)
+	if len(q.head)+len(q.tail) > q.config.budget && len(q.tail) >= q.config.segmentSize {
+		if err := q.spill(); err != nil {
+			q.err = err
+		}
+	}
+}
+
+// Dequeue removes the element at the front of the queue and returns that element
+// If the queue is empty or the next segment cannot be read back, b return false, see Err
+func (q *Queue[T]) Dequeue() (t T, b bool) {
+	t, err := q.DequeueE()
+	return t, err == nil
+}
+
+// Peek returns the element at the front of the queue
+// If the queue is empty or the next segment cannot be read back, b return false, see Err
+func (q *Queue[T]) Peek() (t T, b bool) {
+	t, err := q.PeekE()
+	return t, err == nil
+}
+
+// DequeueE removes the element at the front of the queue and returns that element
+// If the queue is empty, errs.ErrEmpty is returned, and the error of reading the next segment if it fails
+func (q *Queue[T]) DequeueE() (t T, err error) {
+	if t, err = q.PeekE(); err != nil {
+		return t, err
+	}
+	var zero T
+	q.head[0] = zero
+	q.head = q.head[1:]
+	return t, nil
+}
+
+// PeekE returns the element at the front of the queue
+// If the queue is empty, errs.ErrEmpty is returned, and the error of reading the next segment if it fails
+func (q *Queue[T]) PeekE() (t T, err error) {
+	if q.closed {
+		return t, ErrClosed
+	}
+	if len(q.head) == 0 {
+		if err = q.refill(); err != nil {
+			q.err = err
+			return t, err
+		}
+	}
+	if len(q.head) == 0 {
+		return t, errs.ErrEmpty
+	}
+	return q.head[0], nil
+}
+
+// IsEmpty checks whether the queue is empty
+func (q *Queue[T]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+// Size returns the size of the queue, including the elements on disk
+func (q *Queue[T]) Size() int {
+	return len(q.head) + q.spilledCount + len(q.tail)
+}
+
+// Err returns the last error met while spilling or reading back the elements
+func (q *Queue[T]) Err() error {
+	return q.err
+}
+
+// Close removes the files of the queue and discards its elements
+func (q *Queue[T]) Close() error {
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	q.head, q.spilled, q.spilledCount, q.tail = nil, nil, 0, nil
+	return q.store.close()
+}
+
+// spill writes the oldest segment of the back elements to disk
+func (q *Queue[T]) spill() error {
+	seg, err := q.store.write(q.tail[:q.config.segmentSize])
+	if err != nil {
+		return err
+	}
+	q.spilled = append(q.spilled, seg)
+	q.spilledCount += seg.count
+	n := copy(q.tail, q.tail[seg.count:])
+	clear(q.tail[n:])
+	q.tail = q.tail[:n]
+	return nil
+}
+
+// refill moves the next elements to the front, which is empty: the oldest segment on disk, or else the back elements
+func (q *Queue[T]) refill() error {
+	if len(q.spilled) == 0 {
+		q.head, q.tail = q.tail, q.head[:0]
+		return nil
+	}
+	seg := q.spilled[0]
+	elements, err := q.store.read(seg)
+	if err != nil {
+		return err
+	}
+	q.spilled = q.spilled[1:]
+	q.spilledCount -= seg.count
+	q.head = elements
+	// the elements are safe in memory, a file left behind is removed by Close
+	if err := q.store.remove(seg); err != nil {
+		q.err = err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spill
+
+import (
+	"os"
+	"testing"
+
+	"github.com/chenmingyong0423/algorithms/codec"
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue(t *testing.T) {
+	q, err := NewQueue[int](codec.GobCodec[int]{}, WithDir(t.TempDir()), WithSegmentSize(4), WithMemoryBudget(8))
+	require.NoError(t, err)
+	_, ok := q.Dequeue()
+	assert.False(t, ok)
+	_, err = q.DequeueE()
+	assert.Equal(t, errs.ErrEmpty, err)
+
+	next := 0
+	// interleaves the operations so that the elements are in memory at both ends and on disk in between
+	for i := 0; i < 100; i++ {
+		q.Enqueue(i)
+		if i%3 == 0 {
+			v, ok := q.Dequeue()
+			assert.True(t, ok)
+			assert.Equal(t, next, v)
+			next++
+		}
+		assert.LessOrEqual(t, len(q.head)+len(q.tail), 8+4)
+	}
+	assert.Equal(t, 100-next, q.Size())
+	assert.NotEmpty(t, segments(t, q.store.dir))
+	for ; next < 100; next++ {
+		v, ok := q.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, next, v)
+		v, ok = q.Dequeue()
+		assert.True(t, ok)
+		assert.Equal(t, next, v)
+	}
+	assert.True(t, q.IsEmpty())
+	assert.Empty(t, segments(t, q.store.dir))
+	assert.NoError(t, q.Err())
+
+	q.Enqueue(1)
+	assert.NoError(t, q.Close())
+	_, err = os.Stat(q.store.dir)
+	assert.True(t, os.IsNotExist(err))
+	_, err = q.DequeueE()
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestQueue_Corrupt(t *testing.T) {
+	q, err := NewQueue[int](codec.JSONCodec[int]{}, WithDir(t.TempDir()), WithSegmentSize(2), WithMemoryBudget(4))
+	require.NoError(t, err)
+	defer q.Close()
+	for i := 0; i < 6; i++ {
+		q.Enqueue(i)
+	}
+	paths := segments(t, q.store.dir)
+	require.Len(t, paths, 1)
+	// truncates the file, as a crash while writing it in place would
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(paths[0], data[:len(data)-1], 0o600))
+
+	_, err = q.DequeueE()
+	assert.ErrorIs(t, err, ErrCorruptSegment)
+}
@@ -0,0 +1,215 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spill implements a stack and a queue keeping their hot elements in memory
+// and spilling the cold ones to temporary files, for workloads larger than memory.
+package spill
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chenmingyong0423/algorithms/codec"
+)
+
+// ErrCorruptSegment is returned when a spilled segment fails its checks.
+var ErrCorruptSegment = errors.New("spill: corrupt segment")
+
+// ErrClosed is returned by the operations on a closed collection.
+var ErrClosed = errors.New("spill: closed")
+
+// segmentMagic starts every segment file, it ends with the version of the format
+var segmentMagic = [8]byte{'S', 'P', 'I', 'L', 'L', 'S', 'G', 1}
+
+// segmentFile is a segment spilled to disk
+type segmentFile struct {
+	path  string
+	count int
+}
+
+// segmentStore writes and reads the segments of a collection in its own temporary directory.
+//
+// A segment file is made of:
+//
+//	magic   [8]byte
+//	count   uint32, little endian
+//	records count times: uvarint length, then the encoded element
+//	crc     uint32, little endian, CRC-32 (IEEE) of all the bytes before it
+//
+// A segment is written to a temporary file, synced and renamed, so a crash never leaves a partial segment
+// under its final name, and the CRC detects the segments damaged afterwards.
+type segmentStore[T any] struct {
+	dir   string
+	codec codec.Codec[T]
+	next  int
+}
+
+func newSegmentStore[T any](parent string, codec codec.Codec[T]) (*segmentStore[T], error) {
+	dir, err := os.MkdirTemp(parent, "spill-*")
+	if err != nil {
+		return nil, err
+	}
+	return &segmentStore[T]{dir: dir, codec: codec}, nil
+}
+
+// write writes the elements to a new segment file
+func (s *segmentStore[T]) write(elements []T) (seg segmentFile, err error) {
+	tmp, err := os.CreateTemp(s.dir, "segment-*.tmp")
+	if err != nil {
+		return seg, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	crc := crc32.NewIEEE()
+	w := bufio.NewWriter(io.MultiWriter(tmp, crc))
+	header := binary.LittleEndian.AppendUint32(segmentMagic[:], uint32(len(elements)))
+	if _, err = w.Write(header); err != nil {
+		return seg, err
+	}
+	var length [binary.MaxVarintLen64]byte
+	for _, e := range elements {
+		data, err := s.codec.Encode(e)
+		if err != nil {
+			return seg, err
+		}
+		n := binary.PutUvarint(length[:], uint64(len(data)))
+		if _, err = w.Write(length[:n]); err != nil {
+			return seg, err
+		}
+		if _, err = w.Write(data); err != nil {
+			return seg, err
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return seg, err
+	}
+	if _, err = tmp.Write(binary.LittleEndian.AppendUint32(nil, crc.Sum32())); err != nil {
+		return seg, err
+	}
+	if err = tmp.Sync(); err != nil {
+		return seg, err
+	}
+	if err = tmp.Close(); err != nil {
+		return seg, err
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("segment-%08d.seg", s.next))
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return seg, err
+	}
+	s.next++
+	return segmentFile{path: path, count: len(elements)}, nil
+}
+
+// read reads the elements of a segment file
+func (s *segmentStore[T]) read(seg segmentFile) ([]T, error) {
+	data, err := os.ReadFile(seg.path)
+	if err != nil {
+		return nil, err
+	}
+	const headerSize, crcSize = len(segmentMagic) + 4, 4
+	if len(data) < headerSize+crcSize || !bytes.Equal(data[:len(segmentMagic)], segmentMagic[:]) {
+		return nil, fmt.Errorf("%w: %s: bad header", ErrCorruptSegment, seg.path)
+	}
+	body, sum := data[:len(data)-crcSize], binary.LittleEndian.Uint32(data[len(data)-crcSize:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, fmt.Errorf("%w: %s: checksum mismatch", ErrCorruptSegment, seg.path)
+	}
+	count := int(binary.LittleEndian.Uint32(body[len(segmentMagic):]))
+	if count != seg.count {
+		return nil, fmt.Errorf("%w: %s: %d elements instead of %d", ErrCorruptSegment, seg.path, count, seg.count)
+	}
+	elements := make([]T, 0, count)
+	records := body[headerSize:]
+	for i := 0; i < count; i++ {
+		length, n := binary.Uvarint(records)
+		if n <= 0 || uint64(len(records)-n) < length {
+			return nil, fmt.Errorf("%w: %s: truncated record %d", ErrCorruptSegment, seg.path, i)
+		}
+		e, err := s.codec.Decode(records[n : n+int(length)])
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, e)
+		records = records[n+int(length):]
+	}
+	return elements, nil
+}
+
+// remove removes a segment file once its elements are back in memory
+func (s *segmentStore[T]) remove(seg segmentFile) error {
+	return os.Remove(seg.path)
+}
+
+// close removes the directory and all the segments in it
+func (s *segmentStore[T]) close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// Option configures a Stack or a Queue.
+type Option func(c *config)
+
+type config struct {
+	dir         string
+	segmentSize int
+	budget      int
+}
+
+// WithDir sets the directory in which the temporary directory of the segments is created, os.TempDir() by default.
+func WithDir(dir string) Option {
+	return func(c *config) {
+		c.dir = dir
+	}
+}
+
+// WithSegmentSize sets the number of elements spilled to a file at once, 1024 by default.
+func WithSegmentSize(size int) Option {
+	return func(c *config) {
+		c.segmentSize = size
+	}
+}
+
+// WithMemoryBudget sets the number of elements kept in memory before spilling, 4 segments by default.
+// It is raised to 2 segments if it is lower.
+func WithMemoryBudget(elements int) Option {
+	return func(c *config) {
+		c.budget = elements
+	}
+}
+
+func newConfig(opts []Option) config {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.segmentSize <= 0 {
+		c.segmentSize = 1024
+	}
+	if c.budget <= 0 {
+		c.budget = 4 * c.segmentSize
+	}
+	c.budget = max(c.budget, 2*c.segmentSize)
+	return c
+}
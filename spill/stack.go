@@ -0,0 +1,169 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spill
+
+import (
+	"github.com/chenmingyong0423/algorithms/codec"
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/stack"
+)
+
+var _ stack.Stack[any] = (*Stack[any])(nil)
+
+// Stack is a stack keeping its top elements in memory and spilling the bottom ones to disk, a segment at a time.
+// When the elements in memory exceed the memory budget, the bottom segment of them is written to a file,
+// which is read back once the elements above it have been popped.
+// It must be closed to remove its files. It is not safe for concurrent use.
+type Stack[T any] struct {
+	store *segmentStore[T]
+	// hot holds the top elements, the top one last
+	hot []T
+	// spilled holds the segments on disk, the one right below hot last
+	spilled      []segmentFile
+	spilledCount int
+
+	config config
+	err    error
+	closed bool
+}
+
+// NewStack returns a new stack spilling its elements encoded by codec, configured by opts.
+// It returns an error if the directory of the segments cannot be created.
+func NewStack[T any](codec codec.Codec[T], opts ...Option) (*Stack[T], error) {
+	c := newConfig(opts)
+	store, err := newSegmentStore[T](c.dir, codec)
+	if err != nil {
+		return nil, err
+	}
+	return &Stack[T]{store: store, config: c}, nil
+}
+
+// Push pushes an element onto the top of the stack
+// If spilling fails, the elements stay in memory and the error is reported by Err
+func (s *Stack[T]) Push(e T) {
+	if s.closed {
+		s.err = ErrClosed
+		return
+	}
+	s.hot = append(s.hot, e)
+	if len(s.hot) > s.config.budget {
+		if err := s.spill(); err != nil {
+			s.err = err
+		}
+	}
+}
+
+// Pop removes the element at the top of the stack and returns that element
+// If the stack is empty or the segment below cannot be read back, b return false, see Err
+func (s *Stack[T]) Pop() (t T, b bool) {
+	t, err := s.PopE()
+	return t, err == nil
+}
+
+// Peek returns the element at the top of the stack
+// If the stack is empty or the segment below cannot be read back, b return false, see Err
+func (s *Stack[T]) Peek() (t T, b bool) {
+	t, err := s.PeekE()
+	return t, err == nil
+}
+
+// PopE removes the element at the top of the stack and returns that element
+// If the stack is empty, errs.ErrEmpty is returned, and the error of reading the segment below if it fails
+func (s *Stack[T]) PopE() (t T, err error) {
+	if t, err = s.PeekE(); err != nil {
+		return t, err
+	}
+	var zero T
+	s.hot[len(s.hot)-1] = zero
+	s.hot = s.hot[:len(s.hot)-1]
+	return t, nil
+}
+
+// PeekE returns the element at the top of the stack
+// If the stack is empty, errs.ErrEmpty is returned, and the error of reading the segment below if it fails
+func (s *Stack[T]) PeekE() (t T, err error) {
+	if s.closed {
+		return t, ErrClosed
+	}
+	if len(s.hot) == 0 {
+		if err = s.load(); err != nil {
+			s.err = err
+			return t, err
+		}
+	}
+	if len(s.hot) == 0 {
+		return t, errs.ErrEmpty
+	}
+	return s.hot[len(s.hot)-1], nil
+}
+
+// IsEmpty checks whether the stack is empty
+func (s *Stack[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Size returns the size of the stack, including the elements on disk
+func (s *Stack[T]) Size() int {
+	return len(s.hot) + s.spilledCount
+}
+
+// Err returns the last error met while spilling or reading back the elements
+func (s *Stack[T]) Err() error {
+	return s.err
+}
+
+// Close removes the files of the stack and discards its elements
+func (s *Stack[T]) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.hot, s.spilled, s.spilledCount = nil, nil, 0
+	return s.store.close()
+}
+
+// spill writes the bottom segment of the elements in memory to disk
+func (s *Stack[T]) spill() error {
+	seg, err := s.store.write(s.hot[:s.config.segmentSize])
+	if err != nil {
+		return err
+	}
+	s.spilled = append(s.spilled, seg)
+	s.spilledCount += seg.count
+	n := copy(s.hot, s.hot[seg.count:])
+	clear(s.hot[n:])
+	s.hot = s.hot[:n]
+	return nil
+}
+
+// load reads back the segment right below the elements in memory, which are none
+func (s *Stack[T]) load() error {
+	if len(s.spilled) == 0 {
+		return nil
+	}
+	seg := s.spilled[len(s.spilled)-1]
+	elements, err := s.store.read(seg)
+	if err != nil {
+		return err
+	}
+	s.spilled = s.spilled[:len(s.spilled)-1]
+	s.spilledCount -= seg.count
+	s.hot = append(s.hot, elements...)
+	// the elements are safe in memory, a file left behind is removed by Close
+	if err := s.store.remove(seg); err != nil {
+		s.err = err
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spill
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chenmingyong0423/algorithms/codec"
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segments returns the segment files in the directory of the store
+func segments(t *testing.T, dir string) []string {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	require.NoError(t, err)
+	return paths
+}
+
+func TestStack(t *testing.T) {
+	parent := t.TempDir()
+	s, err := NewStack[int](codec.GobCodec[int]{}, WithDir(parent), WithSegmentSize(4), WithMemoryBudget(8))
+	require.NoError(t, err)
+	_, ok := s.Pop()
+	assert.False(t, ok)
+	_, err = s.PopE()
+	assert.Equal(t, errs.ErrEmpty, err)
+
+	for i := 0; i < 30; i++ {
+		s.Push(i)
+	}
+	assert.Equal(t, 30, s.Size())
+	assert.LessOrEqual(t, len(s.hot), 8)
+	assert.Len(t, segments(t, s.store.dir), 6)
+	for i := 29; i >= 0; i-- {
+		v, ok := s.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+		v, ok = s.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, s.IsEmpty())
+	assert.Empty(t, segments(t, s.store.dir))
+	assert.NoError(t, s.Err())
+
+	s.Push(1)
+	assert.NoError(t, s.Close())
+	assert.NoError(t, s.Close())
+	_, err = os.Stat(s.store.dir)
+	assert.True(t, os.IsNotExist(err))
+	_, err = s.PopE()
+	assert.Equal(t, ErrClosed, err)
+	s.Push(1)
+	assert.Equal(t, ErrClosed, s.Err())
+}
+
+func TestStack_Corrupt(t *testing.T) {
+	s, err := NewStack[string](codec.JSONCodec[string]{}, WithDir(t.TempDir()), WithSegmentSize(2), WithMemoryBudget(1))
+	require.NoError(t, err)
+	defer s.Close()
+	for _, e := range []string{"a", "b", "c", "d", "e"} {
+		s.Push(e)
+	}
+	paths := segments(t, s.store.dir)
+	require.Len(t, paths, 1)
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(paths[0], data, 0o600))
+
+	for _, want := range []string{"e", "d", "c"} {
+		v, err := s.PopE()
+		assert.NoError(t, err)
+		assert.Equal(t, want, v)
+	}
+	_, err = s.PopE()
+	assert.ErrorIs(t, err, ErrCorruptSegment)
+	assert.ErrorIs(t, s.Err(), ErrCorruptSegment)
+	assert.Equal(t, 2, s.Size())
+}
+
+// failingCodec fails to encode the elements while fail is set
+type failingCodec struct {
+	codec.JSONCodec[int]
+	fail bool
+}
+
+func (c *failingCodec) Encode(e int) ([]byte, error) {
+	if c.fail {
+		return nil, errors.New("failure")
+	}
+	return c.JSONCodec.Encode(e)
+}
+
+func TestStack_SpillFailure(t *testing.T) {
+	codec := &failingCodec{fail: true}
+	s, err := NewStack[int](codec, WithDir(t.TempDir()), WithSegmentSize(2), WithMemoryBudget(4))
+	require.NoError(t, err)
+	defer s.Close()
+	for i := 0; i < 6; i++ {
+		s.Push(i)
+	}
+	// the elements stay in memory
+	assert.EqualError(t, s.Err(), "failure")
+	assert.Len(t, s.hot, 6)
+	assert.Empty(t, segments(t, s.store.dir))
+	// no temporary file is left behind
+	entries, err := os.ReadDir(s.store.dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	codec.fail = false
+	s.Push(6)
+	assert.Len(t, segments(t, s.store.dir), 1)
+	for i := 6; i >= 0; i-- {
+		v, ok := s.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+}
@@ -14,35 +14,122 @@
 
 package stack
 
-var _ Stack[any] = (*ArrayStack[any])(nil)
+import (
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var _ ExtendedStack[any] = (*ArrayStack[any])(nil)
 
 type ArrayStack[T any] struct {
 	elements []T
+	// shared tells whether elements is shared with a snapshot,
+	// in which case len(elements) == cap(elements) so that the next append copies it
+	shared bool
+
+	maxSize   int
+	overflow  option.OverflowPolicy
+	onEvict   func(elements []T)
+	shrink    option.ShrinkPolicy
+	observers option.Observers[T]
+}
+
+// NewArrayStack returns a new stack configured by opts.
+// With option.WithMaxSize, OverflowEvictOldest drops the bottom element to make room for a push
+// and OverflowEvictNewest the top one, the other policies reject the push.
+// The dropped or rejected elements are passed to the function set by option.WithOnEvict.
+func NewArrayStack[T any](opts ...option.Option[T]) *ArrayStack[T] {
+	o := option.Apply(opts...)
+	s := &ArrayStack[T]{
+		maxSize:   o.MaxSize,
+		overflow:  o.Overflow,
+		onEvict:   o.OnEvict,
+		shrink:    o.Shrink,
+		observers: option.NewObservers(o.Hooks),
+	}
+	if o.Capacity > 0 {
+		s.elements = make([]T, 0, o.Capacity)
+	}
+	return s
 }
 
 // NewStackSlice returns a new stack
 func NewStackSlice[T any]() *ArrayStack[T] {
-	return &ArrayStack[T]{}
+	return NewArrayStack[T]()
 }
 
 // NewStackSliceWithSize returns a new stack with size
 func NewStackSliceWithSize[T any](size int) *ArrayStack[T] {
-	return &ArrayStack[T]{
-		elements: make([]T, 0, size),
-	}
+	return NewArrayStack[T](option.WithCapacity[T](size))
 }
 
 // Push pushes an element onto the top of the stack
+// If the stack is full, the overflow policy is applied, see NewArrayStack and PushE
 func (s *ArrayStack[T]) Push(e T) {
+	s.push(e)
+}
+
+// push pushes an element onto the top of the stack after applying the overflow policy
+// If the element is rejected, b return false
+func (s *ArrayStack[T]) push(e T) bool {
+	if s.maxSize > 0 && len(s.elements) >= s.maxSize {
+		switch s.overflow {
+		case option.OverflowEvictOldest:
+			s.evict(s.removeBottom(1)...)
+		case option.OverflowEvictNewest:
+			s.evict(s.PopN(1)...)
+		default:
+			s.evict(e)
+			return false
+		}
+	}
+	// appending to a shared slice copies it since it has no spare capacity
 	s.elements = append(s.elements, e)
+	s.shared = false
+	s.observers.NotifyAdd(len(s.elements)-1, e)
+	return true
+}
+
+// removeBottom removes the n elements at the bottom of the stack, which has at least n elements, in O(size)
+// and returns them from the bottom
+func (s *ArrayStack[T]) removeBottom(n int) []T {
+	if s.shared {
+		s.resize(cap(s.elements))
+	}
+	bottom := append([]T(nil), s.elements[:n]...)
+	copy(s.elements, s.elements[n:])
+	clear(s.elements[len(s.elements)-n:])
+	s.elements = s.elements[:len(s.elements)-n]
+	for _, e := range bottom {
+		s.observers.NotifyRemove(0, e)
+	}
+	return bottom
+}
+
+// evict passes the elements dropped or rejected because of the overflow policy to onEvict
+func (s *ArrayStack[T]) evict(elements ...T) {
+	if s.onEvict != nil && len(elements) > 0 {
+		s.onEvict(elements)
+	}
 }
 
 // Pop removes the element at the top of the stack and returns that element
+// The stack shrinks according to its shrink policy
 func (s *ArrayStack[T]) Pop() (t T, b bool) {
 	if !s.IsEmpty() {
 		lastIdx := len(s.elements) - 1
 		t, b = s.elements[lastIdx], true
-		s.elements = s.elements[:lastIdx]
+		if s.shared {
+			// the slot still belongs to the snapshot, it must not be zeroed
+			s.elements = s.elements[:lastIdx:lastIdx]
+		} else {
+			// the vacated slot is zeroed so that the element can be garbage collected
+			var zero T
+			s.elements[lastIdx] = zero
+			s.elements = s.elements[:lastIdx]
+			s.maybeShrink()
+		}
+		s.observers.NotifyRemove(lastIdx, t)
 	}
 	return
 }
@@ -65,3 +152,262 @@ func (s *ArrayStack[T]) IsEmpty() bool {
 func (s *ArrayStack[T]) Size() int {
 	return len(s.elements)
 }
+
+// Clear removes all the elements of the stack
+// The capacity is kept unless the shrink policy releases it
+func (s *ArrayStack[T]) Clear() {
+	if s.shared {
+		s.elements = nil
+		s.shared = false
+	} else {
+		clear(s.elements)
+		s.elements = s.elements[:0]
+		s.maybeShrink()
+	}
+	s.observers.NotifyClear()
+}
+
+// PushAll pushes the elements in order, the last one ends up on top
+// If the elements do not fit, the overflow policy is applied to all of them at once as by the lists:
+// OverflowReject rejects all of them, OverflowEvictOldest drops the bottom elements
+// and OverflowEvictNewest the top ones, followed by the elements which do not fit even in an empty stack.
+func (s *ArrayStack[T]) PushAll(elements ...T) {
+	if excess := len(s.elements) + len(elements) - s.maxSize; s.maxSize > 0 && excess > 0 {
+		var dropped []T
+		switch s.overflow {
+		case option.OverflowEvictOldest:
+			dropped = s.removeBottom(min(excess, len(s.elements)))
+			// not even the elements fit, keep the ones pushed last
+			if len(elements) > s.maxSize {
+				dropped = append(dropped, elements[:len(elements)-s.maxSize]...)
+				elements = elements[len(elements)-s.maxSize:]
+			}
+		case option.OverflowEvictNewest:
+			dropped = s.PopN(excess)
+			// not even the elements fit, keep the ones pushed first
+			if len(elements) > s.maxSize {
+				dropped = append(dropped, elements[s.maxSize:]...)
+				elements = elements[:s.maxSize]
+			}
+		default:
+			s.evict(elements...)
+			return
+		}
+		s.evict(dropped...)
+	}
+	s.Reserve(len(elements))
+	for _, e := range elements {
+		s.push(e)
+	}
+}
+
+// PopN removes the n elements at the top of the stack, or all of them if there are fewer,
+// and returns them from the top
+func (s *ArrayStack[T]) PopN(n int) []T {
+	n = min(max(n, 0), len(s.elements))
+	elements := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		e, _ := s.Pop()
+		elements = append(elements, e)
+	}
+	return elements
+}
+
+// PeekN returns the n elements at the top of the stack, or all of them if there are fewer, from the top
+func (s *ArrayStack[T]) PeekN(n int) []T {
+	n = min(max(n, 0), len(s.elements))
+	elements := make([]T, 0, n)
+	for i := len(s.elements) - 1; i >= len(s.elements)-n; i-- {
+		elements = append(elements, s.elements[i])
+	}
+	return elements
+}
+
+// PeekAt returns the element at the depth, the top element is at depth 0
+// If there is no element at the depth, b return false
+func (s *ArrayStack[T]) PeekAt(depth int) (t T, b bool) {
+	if depth < 0 || depth >= len(s.elements) {
+		return
+	}
+	return s.elements[len(s.elements)-1-depth], true
+}
+
+// Values returns the elements of the stack from the top to the bottom
+func (s *ArrayStack[T]) Values() []T {
+	return s.PeekN(len(s.elements))
+}
+
+// Each calls fn with the elements from the top to the bottom until fn returns false
+func (s *ArrayStack[T]) Each(fn func(depth int, e T) bool) {
+	for i := len(s.elements) - 1; i >= 0; i-- {
+		if !fn(len(s.elements)-1-i, s.elements[i]) {
+			return
+		}
+	}
+}
+
+// Clone returns a copy of the stack with the same configuration but without the observers
+func (s *ArrayStack[T]) Clone() *ArrayStack[T] {
+	return &ArrayStack[T]{
+		elements: append(make([]T, 0, cap(s.elements)), s.elements...),
+		maxSize:  s.maxSize,
+		overflow: s.overflow,
+		onEvict:  s.onEvict,
+		shrink:   s.shrink,
+	}
+}
+
+// Swap exchanges the two elements at the top of the stack, like SWAP in Forth
+// If there are fewer than two elements, b return false
+func (s *ArrayStack[T]) Swap() bool {
+	return s.Rotate(2)
+}
+
+// Dup pushes a copy of the element at the top of the stack, like DUP in Forth
+// If the stack is empty or full, b return false
+func (s *ArrayStack[T]) Dup() bool {
+	top, ok := s.Peek()
+	if !ok {
+		return false
+	}
+	return s.PushE(top) == nil
+}
+
+// Rotate moves the element at depth n-1 to the top of the stack, like n-1 ROLL in Forth, e.g. Rotate(3) is ROT (2 ROLL).
+// A negative n moves the top element to depth -n-1 instead, e.g. Rotate(-3) is -ROT.
+// The observers are notified of every element replaced.
+// If there are fewer than |n| elements, b return false
+func (s *ArrayStack[T]) Rotate(n int) bool {
+	k := abs(n)
+	if k > len(s.elements) {
+		return false
+	}
+	if k < 2 {
+		return true
+	}
+	if s.shared {
+		s.resize(len(s.elements))
+	}
+	top := s.elements[len(s.elements)-k:]
+	old := append([]T(nil), top...)
+	if n > 0 {
+		copy(top, old[1:])
+		top[k-1] = old[0]
+	} else {
+		copy(top[1:], old)
+		top[0] = old[k-1]
+	}
+	if s.observers.Len() > 0 {
+		for i := range top {
+			s.observers.NotifySet(len(s.elements)-k+i, old[i], top[i])
+		}
+	}
+	return true
+}
+
+// TrimToSize releases the unused capacity of the stack
+func (s *ArrayStack[T]) TrimToSize() {
+	if cap(s.elements) > len(s.elements) {
+		s.resize(len(s.elements))
+	}
+}
+
+// Reserve makes room for n more elements, so that pushing them does not reallocate
+func (s *ArrayStack[T]) Reserve(n int) {
+	if cap(s.elements)-len(s.elements) < n {
+		s.resize(len(s.elements) + n)
+	}
+}
+
+// Cap returns the number of elements the stack can hold without reallocating
+func (s *ArrayStack[T]) Cap() int {
+	return cap(s.elements)
+}
+
+// maybeShrink moves the elements to a smaller slice if the shrink policy says so
+func (s *ArrayStack[T]) maybeShrink() {
+	if s.shrink.ShouldShrink(len(s.elements), cap(s.elements)) {
+		s.resize(s.shrink.Capacity(len(s.elements)))
+	}
+}
+
+// resize moves the elements to a new slice of the capacity, which is no longer shared
+func (s *ArrayStack[T]) resize(capacity int) {
+	elements := make([]T, len(s.elements), capacity)
+	copy(elements, s.elements)
+	s.elements = elements
+	s.shared = false
+}
+
+// PushE pushes an element onto the top of the stack
+// If the stack is full and the overflow policy rejects the element, errs.ErrFull is returned
+func (s *ArrayStack[T]) PushE(e T) error {
+	if !s.push(e) {
+		return errs.ErrFull
+	}
+	return nil
+}
+
+// PopE removes the element at the top of the stack and returns that element
+// If the stack is empty, errs.ErrEmpty is returned
+func (s *ArrayStack[T]) PopE() (T, error) {
+	t, ok := s.Pop()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// PeekE returns the element at the top of the stack
+// If the stack is empty, errs.ErrEmpty is returned
+func (s *ArrayStack[T]) PeekE() (T, error) {
+	t, ok := s.Peek()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// Observe registers the hooks called after the stack has been changed
+// and returns a function unregistering them.
+func (s *ArrayStack[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	return s.observers.Observe(hooks)
+}
+
+// Snapshot is an immutable copy of the elements of an ArrayStack.
+type Snapshot[T any] struct {
+	elements []T
+}
+
+// Size returns the number of elements of the snapshot
+func (s Snapshot[T]) Size() int {
+	return len(s.elements)
+}
+
+// Snapshot returns a snapshot of the stack in O(1).
+// The stack and the snapshot share the elements until the stack is changed, which then copies them.
+func (s *ArrayStack[T]) Snapshot() Snapshot[T] {
+	s.elements = s.elements[:len(s.elements):len(s.elements)]
+	s.shared = true
+	return Snapshot[T]{elements: s.elements}
+}
+
+// Restore restores the elements of the snapshot in O(1), the stack shares them with the snapshot.
+// The observers are notified of a clear followed by the addition of every element.
+func (s *ArrayStack[T]) Restore(snapshot Snapshot[T]) {
+	s.elements = snapshot.elements
+	s.shared = true
+	if s.observers.Len() > 0 {
+		s.observers.NotifyClear()
+		for i, e := range s.elements {
+			s.observers.NotifyAdd(i, e)
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -17,6 +17,8 @@ package stack
 import (
 	"testing"
 
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -201,3 +203,327 @@ func TestNewStackSliceWithSize(t *testing.T) {
 	s := NewStackSliceWithSize[int](10)
 	assert.Equal(t, 10, cap(s.elements))
 }
+
+func TestNewArrayStack(t *testing.T) {
+	var added, removed []int
+	s := NewArrayStack[int](
+		option.WithCapacity[int](4),
+		option.WithMaxSize[int](2),
+		option.WithHooks[int](option.Hooks[int]{
+			OnAdd: func(index int, e int) {
+				added = append(added, e)
+			},
+			OnRemove: func(index int, e int) {
+				removed = append(removed, e)
+			},
+		}),
+	)
+	assert.Equal(t, 4, cap(s.elements))
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+	assert.Equal(t, []int{1, 2}, s.elements)
+	s.Pop()
+	assert.Equal(t, []int{1, 2}, added)
+	assert.Equal(t, []int{2}, removed)
+}
+
+func TestArrayStack_Overflow(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      option.OverflowPolicy
+		push        []int
+		pushAll     []int
+		wantErr     error
+		wantStack   []int
+		wantEvicted []int
+	}{
+		{
+			name:        "reject",
+			policy:      option.OverflowReject,
+			push:        []int{1, 2, 3},
+			pushAll:     []int{4, 5},
+			wantErr:     errs.ErrFull,
+			wantStack:   []int{1, 2},
+			wantEvicted: []int{3, 4, 5},
+		},
+		{
+			name:        "evict oldest",
+			policy:      option.OverflowEvictOldest,
+			push:        []int{1, 2, 3},
+			pushAll:     []int{4, 5},
+			wantStack:   []int{4, 5},
+			wantEvicted: []int{1, 2, 3},
+		},
+		{
+			name:        "evict newest",
+			policy:      option.OverflowEvictNewest,
+			push:        []int{1, 2, 3},
+			pushAll:     []int{4, 5},
+			wantStack:   []int{4, 5},
+			wantEvicted: []int{2, 3, 1},
+		},
+		{
+			name:      "reject fitting",
+			policy:    option.OverflowReject,
+			push:      []int{1},
+			pushAll:   []int{2},
+			wantStack: []int{1, 2},
+		},
+		{
+			name:        "evict oldest more than max size",
+			policy:      option.OverflowEvictOldest,
+			push:        []int{1},
+			pushAll:     []int{2, 3, 4},
+			wantStack:   []int{3, 4},
+			wantEvicted: []int{1, 2},
+		},
+		{
+			name:        "evict newest more than max size",
+			policy:      option.OverflowEvictNewest,
+			push:        []int{1},
+			pushAll:     []int{2, 3, 4},
+			wantStack:   []int{2, 3},
+			wantEvicted: []int{1, 4},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []int
+			s := NewArrayStack[int](
+				option.WithMaxSize[int](2),
+				option.WithOverflowPolicy[int](tc.policy),
+				option.WithOnEvict[int](func(elements []int) {
+					evicted = append(evicted, elements...)
+				}),
+			)
+			var err error
+			for _, e := range tc.push {
+				err = s.PushE(e)
+			}
+			assert.Equal(t, tc.wantErr, err)
+			s.PushAll(tc.pushAll...)
+			assert.Equal(t, tc.wantStack, s.elements)
+			assert.Equal(t, tc.wantEvicted, evicted)
+		})
+	}
+}
+
+func TestArrayStack_Observe(t *testing.T) {
+	var added, removed []int
+	s := NewArrayStack[int]()
+	cancel := s.Observe(option.Hooks[int]{
+		OnAdd: func(index int, e int) {
+			added = append(added, index)
+		},
+		OnRemove: func(index int, e int) {
+			removed = append(removed, index)
+		},
+	})
+	s.Push(1)
+	s.Push(2)
+	s.Pop()
+	cancel()
+	s.Push(3)
+	assert.Equal(t, []int{0, 1}, added)
+	assert.Equal(t, []int{1}, removed)
+}
+
+func TestArrayStack_ErrorVariants(t *testing.T) {
+	s := NewArrayStack[int](option.WithMaxSize[int](1))
+	_, err := s.PopE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = s.PeekE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	assert.NoError(t, s.PushE(1))
+	assert.Equal(t, errs.ErrFull, s.PushE(2))
+	v, err := s.PeekE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = s.PopE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestArrayStack_Snapshot(t *testing.T) {
+	s := NewArrayStack[int](option.WithCapacity[int](8))
+	s.Push(1)
+	s.Push(2)
+	snapshot := s.Snapshot()
+
+	s.Pop()
+	s.Push(3)
+	s.Push(4)
+	assert.Equal(t, []int{1, 3, 4}, s.elements)
+	assert.Equal(t, []int{1, 2}, snapshot.elements)
+
+	s.Restore(snapshot)
+	assert.Equal(t, 2, snapshot.Size())
+	assert.Equal(t, []int{1, 2}, s.elements)
+	s.Pop()
+	s.Push(5)
+	assert.Equal(t, []int{1, 5}, s.elements)
+	assert.Equal(t, []int{1, 2}, snapshot.elements)
+
+	var events []int
+	s.Observe(option.Hooks[int]{
+		OnClear: func() {
+			events = append(events, -1)
+		},
+		OnAdd: func(index int, e int) {
+			events = append(events, e)
+		},
+	})
+	s.Restore(snapshot)
+	assert.Equal(t, []int{-1, 1, 2}, events)
+}
+
+func TestArrayStack_SnapshotEvictOldest(t *testing.T) {
+	s := NewArrayStack[int](
+		option.WithMaxSize[int](2),
+		option.WithOverflowPolicy[int](option.OverflowEvictOldest),
+	)
+	s.Push(1)
+	s.Push(2)
+	snapshot := s.Snapshot()
+	s.Push(3)
+	assert.Equal(t, []int{2, 3}, s.elements)
+	assert.Equal(t, []int{1, 2}, snapshot.elements)
+}
+
+func TestArrayStack_Shrink(t *testing.T) {
+	s := NewArrayStack[int](option.WithShrinkPolicy[int](0.25, 4))
+	for i := 0; i < 64; i++ {
+		s.Push(i)
+	}
+	assert.Equal(t, 64, s.Cap())
+	for s.Size() > 16 {
+		s.Pop()
+	}
+	assert.Equal(t, 32, s.Cap())
+	for s.Size() > 1 {
+		s.Pop()
+	}
+	assert.Equal(t, 4, s.Cap())
+	v, _ := s.Peek()
+	assert.Equal(t, 0, v)
+
+	// a large ratio does not move the elements on every pop
+	s = NewArrayStack[int](option.WithShrinkPolicy[int](0.9, 4))
+	for i := 0; i < 64; i++ {
+		s.Push(i)
+	}
+	moves := 0
+	for !s.IsEmpty() {
+		capacity := s.Cap()
+		s.Pop()
+		if s.Cap() != capacity {
+			moves++
+		}
+	}
+	assert.Equal(t, 4, moves)
+
+	// without a policy the capacity is kept
+	s = NewArrayStack[int]()
+	for i := 0; i < 64; i++ {
+		s.Push(i)
+	}
+	s.Clear()
+	assert.Equal(t, 64, s.Cap())
+	assert.True(t, s.IsEmpty())
+}
+
+func TestArrayStack_ZeroVacatedSlots(t *testing.T) {
+	s := NewArrayStack[*int](option.WithCapacity[*int](4))
+	s.Push(new(int))
+	s.Push(new(int))
+	s.Pop()
+	assert.Nil(t, s.elements[:2][1])
+
+	// the slots shared with a snapshot are kept
+	snapshot := s.Snapshot()
+	s.Pop()
+	assert.NotNil(t, snapshot.elements[0])
+	s.Restore(snapshot)
+	s.Clear()
+	assert.NotNil(t, snapshot.elements[0])
+
+	s.Push(new(int))
+	s.Clear()
+	assert.Nil(t, s.elements[:1][0])
+}
+
+func TestArrayStack_TrimToSizeAndReserve(t *testing.T) {
+	var cleared bool
+	s := NewArrayStack[int](
+		option.WithCapacity[int](8),
+		option.WithHooks[int](option.Hooks[int]{
+			OnClear: func() {
+				cleared = true
+			},
+		}),
+	)
+	s.Push(1)
+	s.Push(2)
+	s.TrimToSize()
+	assert.Equal(t, 2, s.Cap())
+	assert.Equal(t, []int{1, 2}, s.elements)
+
+	s.Reserve(10)
+	assert.Equal(t, 12, s.Cap())
+	s.Reserve(10)
+	assert.Equal(t, 12, s.Cap())
+
+	// reserving room breaks the sharing with a snapshot
+	snapshot := s.Snapshot()
+	s.Reserve(1)
+	s.Push(3)
+	assert.Equal(t, []int{1, 2}, snapshot.elements)
+	assert.Equal(t, []int{1, 2, 3}, s.elements)
+
+	s.Clear()
+	assert.True(t, cleared)
+	assert.Equal(t, 0, s.Size())
+}
+
+func TestArrayStack_Clone(t *testing.T) {
+	var added int
+	s := NewArrayStack[int](
+		option.WithMaxSize[int](3),
+		option.WithHooks[int](option.Hooks[int]{
+			OnAdd: func(index int, e int) {
+				added++
+			},
+		}),
+	)
+	// the whole PushAll is rejected when the elements do not fit
+	s.PushAll(1, 2, 3, 4)
+	assert.Empty(t, s.elements)
+	s.PushAll(1, 2, 3)
+	assert.Equal(t, []int{1, 2, 3}, s.elements)
+	assert.False(t, s.Dup())
+	clone := s.Clone()
+	clone.Pop()
+	clone.Push(5)
+	clone.Push(6)
+	assert.Equal(t, []int{1, 2, 5}, clone.elements)
+	assert.Equal(t, []int{1, 2, 3}, s.elements)
+	assert.Equal(t, 3, added)
+}
+
+func TestArrayStack_RotateSnapshot(t *testing.T) {
+	var sets [][3]int
+	s := NewArrayStack[int]()
+	s.PushAll(1, 2, 3)
+	snapshot := s.Snapshot()
+	s.Observe(option.Hooks[int]{
+		OnSet: func(index int, old, e int) {
+			sets = append(sets, [3]int{index, old, e})
+		},
+	})
+	assert.True(t, s.Swap())
+	assert.Equal(t, []int{1, 3, 2}, s.elements)
+	assert.Equal(t, []int{1, 2, 3}, snapshot.elements)
+	assert.Equal(t, [][3]int{{1, 2, 3}, {2, 3, 2}}, sets)
+}
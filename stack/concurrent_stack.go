@@ -0,0 +1,199 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"sync"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var _ Stack[any] = (*ConcurrentStack[any])(nil)
+
+// ConcurrentStack wraps a Stack with a lock so that it is safe for concurrent use.
+type ConcurrentStack[T any] struct {
+	stack Stack[T]
+	lock  *sync.RWMutex
+
+	// notFull is signalled when elements are removed, it is only used by option.OverflowBlock
+	notFull *sync.Cond
+	maxSize int
+}
+
+// NewDefaultConcurrentStack returns a new ConcurrentStack with the default ArrayStack.
+// The opts configure both the ConcurrentStack and the ArrayStack.
+func NewDefaultConcurrentStack[T any](opts ...option.Option[T]) *ConcurrentStack[T] {
+	return NewConcurrentStack[T](NewArrayStack[T](opts...), opts...)
+}
+
+// NewConcurrentStack returns a new ConcurrentStack wrapping the stack.
+// The ConcurrentStack only honours option.WithMaxSize together with option.OverflowBlock:
+// Push blocks until there is room.
+func NewConcurrentStack[T any](stack Stack[T], opts ...option.Option[T]) *ConcurrentStack[T] {
+	o := option.Apply(opts...)
+	s := &ConcurrentStack[T]{
+		stack: stack,
+		lock:  &sync.RWMutex{},
+	}
+	if o.Overflow == option.OverflowBlock {
+		s.maxSize = o.MaxSize
+	}
+	s.notFull = sync.NewCond(s.lock)
+	return s
+}
+
+func (s *ConcurrentStack[T]) Push(e T) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.waitForRoom()
+	s.stack.Push(e)
+}
+
+func (s *ConcurrentStack[T]) Pop() (T, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.pop()
+}
+
+func (s *ConcurrentStack[T]) Peek() (T, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.stack.Peek()
+}
+
+func (s *ConcurrentStack[T]) IsEmpty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.stack.IsEmpty()
+}
+
+func (s *ConcurrentStack[T]) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.stack.Size()
+}
+
+// PushE pushes an element onto the top of the stack
+// If the wrapped stack rejects the element, errs.ErrFull is returned,
+// the wrapped stacks which do not have a PushE method never reject it
+func (s *ConcurrentStack[T]) PushE(e T) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.waitForRoom()
+	return s.pushE(e)
+}
+
+// pushE pushes an element onto the wrapped stack, it must be called with the write lock held.
+func (s *ConcurrentStack[T]) pushE(e T) error {
+	if stack, ok := s.stack.(interface{ PushE(e T) error }); ok {
+		return stack.PushE(e)
+	}
+	s.stack.Push(e)
+	return nil
+}
+
+// PopE removes the element at the top of the stack and returns that element
+// If the stack is empty, errs.ErrEmpty is returned
+func (s *ConcurrentStack[T]) PopE() (T, error) {
+	t, ok := s.Pop()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// PeekE returns the element at the top of the stack
+// If the stack is empty, errs.ErrEmpty is returned
+func (s *ConcurrentStack[T]) PeekE() (T, error) {
+	t, ok := s.Peek()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// PopIf removes the element at the top of the stack and returns that element if pred returns true for it,
+// atomically. pred is called with the lock held, so it must not call the methods of the stack.
+// If the stack is empty or pred returns false, b return false
+func (s *ConcurrentStack[T]) PopIf(pred func(e T) bool) (t T, b bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if top, ok := s.stack.Peek(); !ok || !pred(top) {
+		return
+	}
+	return s.pop()
+}
+
+// PushIfSizeBelow pushes an element onto the top of the stack if the stack has fewer than n elements, atomically.
+// It never blocks. If the element is not pushed, b return false
+func (s *ConcurrentStack[T]) PushIfSizeBelow(e T, n int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	size := s.stack.Size()
+	if size >= n || s.maxSize > 0 && size >= s.maxSize {
+		return false
+	}
+	return s.pushE(e) == nil
+}
+
+// Drain removes all the elements of the stack atomically and returns them from the top.
+func (s *ConcurrentStack[T]) Drain() []T {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	elements := make([]T, 0, s.stack.Size())
+	for e, ok := s.stack.Pop(); ok; e, ok = s.stack.Pop() {
+		elements = append(elements, e)
+	}
+	if len(elements) > 0 {
+		s.notFull.Broadcast()
+	}
+	return elements
+}
+
+// Observe registers the hooks called after the stack has been changed
+// and returns a function unregistering them.
+// The hooks are called with the lock held, so they must not call the methods of the stack.
+// It panics if the wrapped stack is not observable.
+func (s *ConcurrentStack[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	unobserve := s.stack.(linkedlist.Observable[T]).Observe(hooks)
+	return func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		unobserve()
+	}
+}
+
+// pop removes the top element, it must be called with the write lock held
+func (s *ConcurrentStack[T]) pop() (T, bool) {
+	t, ok := s.stack.Pop()
+	if ok {
+		s.notFull.Broadcast()
+	}
+	return t, ok
+}
+
+// waitForRoom blocks until there is room for an element, it must be called with the write lock held.
+func (s *ConcurrentStack[T]) waitForRoom() {
+	if s.maxSize <= 0 {
+		return
+	}
+	for s.stack.Size() >= s.maxSize {
+		s.notFull.Wait()
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentStack_OverflowBlock(t *testing.T) {
+	s := NewDefaultConcurrentStack[int](
+		option.WithMaxSize[int](2),
+		option.WithOverflowPolicy[int](option.OverflowBlock),
+	)
+	s.Push(1)
+	s.Push(2)
+
+	pushed := make(chan struct{})
+	go func() {
+		s.Push(3)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push should block while the stack is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	top, ok := s.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 2, top)
+	<-pushed
+	assert.Equal(t, []int{3, 1}, s.Drain())
+	assert.False(t, s.PushIfSizeBelow(1, 0))
+}
+
+func TestConcurrentStack_Concurrent(t *testing.T) {
+	s := NewDefaultConcurrentStack[int](
+		option.WithMaxSize[int](8),
+		option.WithOverflowPolicy[int](option.OverflowBlock),
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Push(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; {
+				if _, ok := s.Pop(); ok {
+					j++
+				}
+				assert.LessOrEqual(t, s.Size(), 8)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.True(t, s.IsEmpty())
+}
+
+func TestConcurrentStack_PushIfSizeBelow(t *testing.T) {
+	s := NewConcurrentStack[int](NewLinkedListStack[int]())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.PushIfSizeBelow(j, 10)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, s.Size())
+	assert.False(t, s.PushIfSizeBelow(0, 10))
+	assert.True(t, s.PushIfSizeBelow(0, 11))
+
+	// the wrapped stack evicts its bottom element to make room
+	s = NewDefaultConcurrentStack[int](
+		option.WithMaxSize[int](2),
+		option.WithOverflowPolicy[int](option.OverflowEvictOldest),
+	)
+	s.Push(1)
+	s.Push(2)
+	assert.True(t, s.PushIfSizeBelow(3, 10))
+	v, _ := s.Peek()
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, s.Size())
+
+	// the wrapped stack rejects the element
+	s = NewDefaultConcurrentStack[int](option.WithMaxSize[int](1))
+	s.Push(1)
+	assert.False(t, s.PushIfSizeBelow(2, 10))
+}
+
+func TestConcurrentStack_PopIf(t *testing.T) {
+	s := NewDefaultConcurrentStack[int]()
+	_, ok := s.PopIf(func(e int) bool {
+		return true
+	})
+	assert.False(t, ok)
+	for i := 0; i < 1000; i++ {
+		s.Push(i % 2)
+	}
+	// the goroutines race to pop with both predicates, every element is popped exactly once
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	popped := 0
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				_, ok := s.PopIf(func(e int) bool {
+					return e == 1
+				})
+				if !ok {
+					top, ok := s.PopIf(func(e int) bool {
+						return e == 0
+					})
+					if !ok {
+						if s.IsEmpty() {
+							return
+						}
+						continue
+					}
+					assert.Equal(t, 0, top)
+				}
+				lock.Lock()
+				popped++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, popped)
+	assert.Empty(t, s.Drain())
+}
+
+func TestConcurrentStack_ErrorVariants(t *testing.T) {
+	s := NewDefaultConcurrentStack[int](option.WithMaxSize[int](1))
+	_, err := s.PopE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = s.PeekE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	assert.NoError(t, s.PushE(1))
+	assert.Equal(t, errs.ErrFull, s.PushE(2))
+	v, err := s.PeekE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = s.PopE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	s = NewDefaultConcurrentStack[int](
+		option.WithMaxSize[int](1),
+		option.WithOverflowPolicy[int](option.OverflowEvictOldest),
+	)
+	assert.NoError(t, s.PushE(1))
+	assert.NoError(t, s.PushE(2))
+	v, err = s.PeekE()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestConcurrentStack_Observe(t *testing.T) {
+	var added []int
+	s := NewDefaultConcurrentStack[int]()
+	cancel := s.Observe(option.Hooks[int]{
+		OnAdd: func(index int, e int) {
+			added = append(added, e)
+		},
+	})
+	s.Push(1)
+	cancel()
+	s.Push(2)
+	assert.Equal(t, []int{1}, added)
+
+	assert.Panics(t, func() {
+		NewConcurrentStack[int](NewMinMaxStack[int](func(a, b int) int {
+			return a - b
+		})).Observe(option.Hooks[int]{})
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"math/rand"
+	"runtime"
+	"sync/atomic"
+)
+
+var _ Stack[any] = (*EliminationStack[any])(nil)
+
+// offer states of an elimination slot
+const (
+	offerWaiting int32 = iota
+	offerClaimed
+	offerDone
+	offerCanceled
+)
+
+// offer is a push or a pop waiting in the elimination array for the opposite operation
+type offer[T any] struct {
+	value T
+	push  bool
+	state atomic.Int32
+}
+
+// lockFreeNode is a node of a lock-free stack, it is never changed once pushed
+type lockFreeNode[T any] struct {
+	value T
+	next  *lockFreeNode[T]
+}
+
+// EliminationStack is a lock-free stack, a Treiber stack with an elimination array:
+// when a push or a pop loses the race for the top, it waits a little in a random slot of the array
+// for the opposite operation, and both complete without touching the top.
+// Under high contention, the pushes and pops mostly cancel each other out instead of retrying on the top.
+type EliminationStack[T any] struct {
+	top  atomic.Pointer[lockFreeNode[T]]
+	size atomic.Int64
+
+	slots []atomic.Pointer[offer[T]]
+	spins int
+}
+
+// NewEliminationStack returns a new stack with an elimination array of width slots,
+// in which the operations wait for spins rounds, yielding the processor at each round.
+// A wider array spreads the operations and lowers the chance of meeting, more spins raise it but add latency.
+// If width is not positive, it is half of GOMAXPROCS, and if spins is not positive, 32 rounds are used.
+func NewEliminationStack[T any](width, spins int) *EliminationStack[T] {
+	if width <= 0 {
+		width = max(runtime.GOMAXPROCS(0)/2, 1)
+	}
+	if spins <= 0 {
+		spins = 32
+	}
+	return &EliminationStack[T]{
+		slots: make([]atomic.Pointer[offer[T]], width),
+		spins: spins,
+	}
+}
+
+// Push pushes an element onto the top of the stack
+func (s *EliminationStack[T]) Push(e T) {
+	n := &lockFreeNode[T]{value: e}
+	for {
+		n.next = s.top.Load()
+		if s.top.CompareAndSwap(n.next, n) {
+			s.size.Add(1)
+			return
+		}
+		o := &offer[T]{value: e, push: true}
+		if s.exchange(o) {
+			return
+		}
+	}
+}
+
+// Pop removes the element at the top of the stack and returns that element
+func (s *EliminationStack[T]) Pop() (t T, b bool) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			return
+		}
+		if s.top.CompareAndSwap(top, top.next) {
+			s.size.Add(-1)
+			return top.value, true
+		}
+		o := &offer[T]{}
+		if s.exchange(o) {
+			return o.value, true
+		}
+	}
+}
+
+// Peek returns the element at the top of the stack
+func (s *EliminationStack[T]) Peek() (t T, b bool) {
+	top := s.top.Load()
+	if top == nil {
+		return
+	}
+	return top.value, true
+}
+
+// IsEmpty checks whether the stack is empty
+func (s *EliminationStack[T]) IsEmpty() bool {
+	return s.top.Load() == nil
+}
+
+// Size returns the size of the stack, it may be off while operations are in progress
+func (s *EliminationStack[T]) Size() int {
+	return max(int(s.size.Load()), 0)
+}
+
+// exchange tries to complete the offer with an opposite one in a random slot of the elimination array.
+// It returns false if no opposite offer showed up, and the operation has to be retried on the top.
+func (s *EliminationStack[T]) exchange(o *offer[T]) bool {
+	slot := &s.slots[rand.Intn(len(s.slots))]
+	if other := slot.Load(); other != nil {
+		if other.push == o.push || !other.state.CompareAndSwap(offerWaiting, offerClaimed) {
+			return false
+		}
+		if o.push {
+			other.value = o.value
+		} else {
+			o.value = other.value
+		}
+		other.state.Store(offerDone)
+		return true
+	}
+	if !slot.CompareAndSwap(nil, o) {
+		return false
+	}
+	defer slot.CompareAndSwap(o, nil)
+	for i := 0; i < s.spins && o.state.Load() == offerWaiting; i++ {
+		runtime.Gosched()
+	}
+	if o.state.CompareAndSwap(offerWaiting, offerCanceled) {
+		return false
+	}
+	// claimed, the value is about to be exchanged
+	for o.state.Load() != offerDone {
+		runtime.Gosched()
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEliminationStack(t *testing.T) {
+	s := NewEliminationStack[int](0, 0)
+	_, ok := s.Pop()
+	assert.False(t, ok)
+	_, ok = s.Peek()
+	assert.False(t, ok)
+	assert.True(t, s.IsEmpty())
+	for i := 0; i < 3; i++ {
+		s.Push(i)
+	}
+	assert.Equal(t, 3, s.Size())
+	v, ok := s.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	for i := 2; i >= 0; i-- {
+		v, ok := s.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+}
+
+func TestEliminationStack_Exchange(t *testing.T) {
+	s := NewEliminationStack[int](1, 1<<20)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for !s.exchange(&offer[int]{value: 42, push: true}) {
+		}
+	}()
+	// the pop meets the push whichever arrives first in the slot
+	pop := &offer[int]{}
+	for !s.exchange(pop) {
+		pop = &offer[int]{}
+	}
+	wg.Wait()
+	assert.Equal(t, 42, pop.value)
+	assert.Nil(t, s.slots[0].Load())
+
+	// offers of the same kind do not meet
+	s = NewEliminationStack[int](1, 1)
+	assert.False(t, s.exchange(&offer[int]{push: true}))
+	assert.Nil(t, s.slots[0].Load())
+}
+
+func TestEliminationStack_Concurrent(t *testing.T) {
+	const goroutines, n = 8, 2000
+	s := NewEliminationStack[int](2, 8)
+	var wg sync.WaitGroup
+	popped := make([][]int, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				s.Push(i*n + j)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for len(popped[i]) < n {
+				if v, ok := s.Pop(); ok {
+					popped[i] = append(popped[i], v)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Size())
+	// every element is popped exactly once
+	seen := make([]bool, goroutines*n)
+	for _, values := range popped {
+		for _, v := range values {
+			assert.False(t, seen[v])
+			seen[v] = true
+		}
+	}
+}
+
+func BenchmarkEliminationStack(b *testing.B) {
+	stacks := []struct {
+		name  string
+		stack func() Stack[int]
+	}{
+		{
+			name: "EliminationStack",
+			stack: func() Stack[int] {
+				return NewEliminationStack[int](0, 0)
+			},
+		},
+		{
+			name: "ConcurrentStack(ArrayStack)",
+			stack: func() Stack[int] {
+				return NewDefaultConcurrentStack[int]()
+			},
+		},
+	}
+	for _, st := range stacks {
+		for goroutines := 1; goroutines <= 64; goroutines *= 2 {
+			b.Run(fmt.Sprintf("%s/goroutines=%d", st.name, goroutines), func(b *testing.B) {
+				s := st.stack()
+				var wg sync.WaitGroup
+				b.ResetTimer()
+				for i := 0; i < goroutines; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						// each goroutine pushes and pops its share of the b.N pairs
+						for j := i; j < b.N; j += goroutines {
+							s.Push(j)
+							s.Pop()
+						}
+					}(i)
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"reflect"
+
+	"github.com/chenmingyong0423/algorithms/internal/hashing"
+)
+
+// equalValues checks whether the elements enumerated by each from the top are the elements of other, compared with eq.
+// If eq is nil, the elements are compared with ==, the elements which are not comparable are never equal.
+func equalValues[T any](size int, each func(fn func(depth int, e T) bool), other ExtendedStack[T], eq func(a, b T) bool) bool {
+	if eq == nil {
+		eq = defaultEqual[T]
+	}
+	if size != other.Size() {
+		return false
+	}
+	values := other.Values()
+	if size != len(values) {
+		return false
+	}
+	result := true
+	each(func(depth int, e T) bool {
+		result = eq(e, values[depth])
+		return result
+	})
+	return result
+}
+
+// defaultEqual compares the elements with ==, the elements which are not comparable are never equal
+func defaultEqual[T any](a, b T) bool {
+	x, y := any(a), any(b)
+	if x == nil || y == nil {
+		return x == y
+	}
+	// == panics on the values which are not comparable, e.g. slices or structs holding them
+	if !reflect.ValueOf(x).Comparable() || !reflect.ValueOf(y).Comparable() {
+		return false
+	}
+	return x == y
+}
+
+// Equal checks whether the stack and other have the same elements in the same order, compared with eq.
+// If eq is nil, the elements are compared with ==, the elements which are not comparable, e.g. slices, are never equal.
+func (s *ArrayStack[T]) Equal(other ExtendedStack[T], eq func(a, b T) bool) bool {
+	return equalValues(len(s.elements), s.Each, other, eq)
+}
+
+// DeepClone returns a copy of the stack with the same configuration but without the observers,
+// the elements are copied by clone
+func (s *ArrayStack[T]) DeepClone(clone func(e T) T) *ArrayStack[T] {
+	c := s.Clone()
+	for i, e := range c.elements {
+		c.elements[i] = clone(e)
+	}
+	return c
+}
+
+// Hash returns a hash of the elements from the top to the bottom, hashed by hash.
+// Stacks with equal elements have the same hash, whatever their type,
+// and the hash is stable across processes as long as hash is.
+func (s *ArrayStack[T]) Hash(hash func(e T) uint64) uint64 {
+	return hashing.Sequence(s.Each, hash)
+}
+
+// Equal checks whether the stack and other have the same elements in the same order, compared with eq.
+// If eq is nil, the elements are compared with ==, the elements which are not comparable, e.g. slices, are never equal.
+func (l *LinkedListStack[T]) Equal(other ExtendedStack[T], eq func(a, b T) bool) bool {
+	return equalValues(l.list.Size(), l.Each, other, eq)
+}
+
+// DeepClone returns a copy of the stack without the observers, the elements are copied by clone, see Clone
+func (l *LinkedListStack[T]) DeepClone(clone func(e T) T) *LinkedListStack[T] {
+	values := l.list.Values()
+	for i, e := range values {
+		values[i] = clone(e)
+	}
+	list := l.newList()
+	list.Add(values...)
+	return &LinkedListStack[T]{
+		list:    list,
+		newList: l.newList,
+	}
+}
+
+// Hash returns a hash of the elements from the top to the bottom, see ArrayStack.Hash
+func (l *LinkedListStack[T]) Hash(hash func(e T) uint64) uint64 {
+	return hashing.Sequence(l.Each, hash)
+}
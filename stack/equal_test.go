@@ -0,0 +1,110 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStack_Equal(t *testing.T) {
+	stacks := map[string]func(elements ...int) ExtendedStack[int]{
+		"array": func(elements ...int) ExtendedStack[int] {
+			s := NewArrayStack[int]()
+			s.PushAll(elements...)
+			return s
+		},
+		"linked list": func(elements ...int) ExtendedStack[int] {
+			s := NewLinkedListStack[int]()
+			s.PushAll(elements...)
+			return s
+		},
+		"linked list over a doubly linked list": func(elements ...int) ExtendedStack[int] {
+			s := NewLinkedListStackWithList[int](linkedlist.NewDoublyLinkedList[int]())
+			s.PushAll(elements...)
+			return s
+		},
+	}
+	type equaler interface {
+		Equal(other ExtendedStack[int], eq func(a, b int) bool) bool
+		Hash(hash func(e int) uint64) uint64
+	}
+	hash := func(e int) uint64 {
+		return uint64(e)
+	}
+	for name, newStack := range stacks {
+		t.Run(name, func(t *testing.T) {
+			s := newStack(1, 2, 3).(equaler)
+			for otherName, newOther := range stacks {
+				assert.True(t, s.Equal(newOther(1, 2, 3), nil), otherName)
+				assert.False(t, s.Equal(newOther(1, 2), nil), otherName)
+				assert.False(t, s.Equal(newOther(3, 2, 1), nil), otherName)
+				assert.True(t, s.Equal(newOther(-1, -2, -3), func(a, b int) bool {
+					return a == -b
+				}), otherName)
+				assert.Equal(t, s.Hash(hash), newOther(1, 2, 3).(equaler).Hash(hash), otherName)
+				assert.NotEqual(t, s.Hash(hash), newOther(3, 2, 1).(equaler).Hash(hash), otherName)
+			}
+		})
+	}
+}
+
+func TestStack_EqualNotComparable(t *testing.T) {
+	s := NewArrayStack[[]int]()
+	s.Push([]int{1})
+	other := NewLinkedListStack[[]int]()
+	other.Push([]int{1})
+	assert.False(t, s.Equal(other, nil))
+	assert.False(t, other.Equal(s, nil))
+	assert.True(t, s.Equal(other, func(a, b []int) bool {
+		return len(a) == len(b) && a[0] == b[0]
+	}))
+}
+
+func TestStack_DeepClone(t *testing.T) {
+	type box struct {
+		v int
+	}
+	clone := func(b *box) *box {
+		return &box{b.v}
+	}
+	array := NewArrayStack[*box](option.WithMaxSize[*box](3))
+	array.PushAll(&box{1}, &box{2})
+	list := NewLinkedListStack[*box](option.WithMaxSize[*box](3))
+	list.PushAll(&box{1}, &box{2})
+	testCases := []struct {
+		name  string
+		stack ExtendedStack[*box]
+		clone ExtendedStack[*box]
+	}{
+		{name: "array", stack: array, clone: array.DeepClone(clone)},
+		{name: "linked list", stack: list, clone: list.DeepClone(clone)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values := tc.stack.Values()
+			assert.Equal(t, values, tc.clone.Values())
+			assert.NotSame(t, values[0], tc.clone.Values()[0])
+			// the copy keeps the max size and is independent of the stack
+			tc.clone.Push(&box{3})
+			tc.clone.Push(&box{4})
+			assert.Equal(t, []*box{{3}, {2}, {1}}, tc.clone.Values())
+			assert.Equal(t, 2, tc.stack.Size())
+		})
+	}
+}
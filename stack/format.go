@@ -0,0 +1,277 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+
+	"github.com/chenmingyong0423/algorithms/internal/format"
+)
+
+var (
+	_ fmt.Formatter = (*ArrayStack[any])(nil)
+	_ fmt.Formatter = (*LinkedListStack[any])(nil)
+	_ fmt.Formatter = (*MinMaxStack[any])(nil)
+	_ fmt.Formatter = (*MonotonicStack[any])(nil)
+	_ fmt.Formatter = (*SegmentedStack[any])(nil)
+	_ fmt.Formatter = (*MinMaxQueue[any])(nil)
+	_ fmt.Formatter = (*MonotonicQueue[any])(nil)
+	_ fmt.Formatter = (*ConcurrentStack[any])(nil)
+	_ fmt.Formatter = (*EliminationStack[any])(nil)
+)
+
+// iterable is implemented by the stacks of the package enumerating their elements without removing them
+type iterable[T any] interface {
+	each(fn func(depth int, e T) bool)
+}
+
+// String returns the elements of the stack from the top to the bottom, e.g. [3 2 1]
+func (s *ArrayStack[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// GoString returns the Go syntax of the stack, e.g. &stack.ArrayStack[int]{3, 2, 1}
+func (s *ArrayStack[T]) GoString() string {
+	return fmt.Sprintf("%#v", s)
+}
+
+// Format implements fmt.Formatter: %v writes the elements from the top, e.g. [3 2 1], %+v with their depths, e.g. [0:3 1:2 2:1],
+// and %#v the Go syntax. The precision limits the number of elements, e.g. %.2v writes [3 2 ... (1 more)],
+// and the width the length of the output, e.g. %80v. The other verbs are applied to the elements, e.g. %x.
+func (s *ArrayStack[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, s, s.Size(), s.each)
+}
+
+// String returns the elements of the stack from the top to the bottom, e.g. [3 2 1]
+func (l *LinkedListStack[T]) String() string {
+	return fmt.Sprint(l)
+}
+
+// GoString returns the Go syntax of the stack, e.g. &stack.LinkedListStack[int]{3, 2, 1}
+func (l *LinkedListStack[T]) GoString() string {
+	return fmt.Sprintf("%#v", l)
+}
+
+// Format implements fmt.Formatter, see ArrayStack.Format.
+func (l *LinkedListStack[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, l, l.Size(), l.each)
+}
+
+// String returns the elements of the stack from the top to the bottom, e.g. [3 2 1]
+func (s *MinMaxStack[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// GoString returns the Go syntax of the stack, e.g. &stack.MinMaxStack[int]{3, 2, 1}
+func (s *MinMaxStack[T]) GoString() string {
+	return fmt.Sprintf("%#v", s)
+}
+
+// Format implements fmt.Formatter, see ArrayStack.Format.
+func (s *MinMaxStack[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, s, s.Size(), s.each)
+}
+
+// String returns the elements of the stack from the top to the bottom, e.g. [3 2 1]
+func (s *MonotonicStack[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// GoString returns the Go syntax of the stack, e.g. &stack.MonotonicStack[int]{3, 2, 1}
+func (s *MonotonicStack[T]) GoString() string {
+	return fmt.Sprintf("%#v", s)
+}
+
+// Format implements fmt.Formatter, see ArrayStack.Format.
+func (s *MonotonicStack[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, s, s.Size(), s.each)
+}
+
+// String returns the elements of the stack from the top to the bottom, e.g. [3 2 1]
+func (s *SegmentedStack[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// GoString returns the Go syntax of the stack, e.g. &stack.SegmentedStack[int]{3, 2, 1}
+func (s *SegmentedStack[T]) GoString() string {
+	return fmt.Sprintf("%#v", s)
+}
+
+// Format implements fmt.Formatter, see ArrayStack.Format.
+func (s *SegmentedStack[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, s, s.size, s.each)
+}
+
+// String returns the elements of the queue from the front to the back, e.g. [1 2 3]
+func (q *MinMaxQueue[T]) String() string {
+	return fmt.Sprint(q)
+}
+
+// GoString returns the Go syntax of the queue, e.g. &stack.MinMaxQueue[int]{1, 2, 3}
+func (q *MinMaxQueue[T]) GoString() string {
+	return fmt.Sprintf("%#v", q)
+}
+
+// Format implements fmt.Formatter, see ArrayStack.Format. %+v writes the positions from the front.
+func (q *MinMaxQueue[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, q, q.Size(), q.each)
+}
+
+// String returns the elements of the queue from the front to the back, e.g. [1 2 3]
+func (q *MonotonicQueue[T]) String() string {
+	return fmt.Sprint(q)
+}
+
+// GoString returns the Go syntax of the queue, e.g. &stack.MonotonicQueue[int]{1, 2, 3}
+func (q *MonotonicQueue[T]) GoString() string {
+	return fmt.Sprintf("%#v", q)
+}
+
+// Format implements fmt.Formatter, see ArrayStack.Format. %+v writes the positions from the front.
+func (q *MonotonicQueue[T]) Format(f fmt.State, verb rune) {
+	format.Format(f, verb, q, q.Size(), q.each)
+}
+
+// String returns the elements of the stack from the top to the bottom, e.g. [3 2 1]
+func (s *ConcurrentStack[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// GoString returns the Go syntax of the stack, e.g. &stack.ConcurrentStack[int]{3, 2, 1}
+func (s *ConcurrentStack[T]) GoString() string {
+	return fmt.Sprintf("%#v", s)
+}
+
+// Format implements fmt.Formatter under the read lock, see ArrayStack.Format.
+// The elements of a wrapped stack enumerating neither through Each nor like the stacks of the package are summarized.
+func (s *ConcurrentStack[T]) Format(f fmt.State, verb rune) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var each func(fn func(depth int, e T) bool)
+	switch stack := s.stack.(type) {
+	case iterable[T]:
+		each = stack.each
+	case ExtendedStack[T]:
+		each = stack.Each
+	default:
+		each = func(func(depth int, e T) bool) {}
+	}
+	format.Format(f, verb, s, s.stack.Size(), each)
+}
+
+// String returns the elements of the stack from the top to the bottom, e.g. [3 2 1]
+func (s *EliminationStack[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// GoString returns the Go syntax of the stack, e.g. &stack.EliminationStack[int]{3, 2, 1}
+func (s *EliminationStack[T]) GoString() string {
+	return fmt.Sprintf("%#v", s)
+}
+
+// Format implements fmt.Formatter, see ArrayStack.Format.
+// The elements are those of the stack at the time Format loads the top, the operations after that are not reflected.
+func (s *EliminationStack[T]) Format(f fmt.State, verb rune) {
+	// the nodes are never changed once pushed, so the nodes below the top are a consistent snapshot
+	top := s.top.Load()
+	// with a precision only the nodes up to it are counted, Size tells how many are left out
+	limit, hasLimit := f.Precision()
+	size := 0
+	for n := top; n != nil && (!hasLimit || size < limit); n = n.next {
+		size++
+	}
+	if hasLimit && size == limit {
+		size = max(size, s.Size())
+	}
+	format.Format(f, verb, s, size, func(fn func(depth int, e T) bool) {
+		depth := 0
+		for n := top; n != nil; n = n.next {
+			if !fn(depth, n.value) {
+				return
+			}
+			depth++
+		}
+	})
+}
+
+// each calls fn with the elements from the top to the bottom until fn returns false
+func (s *ArrayStack[T]) each(fn func(depth int, e T) bool) {
+	s.Each(fn)
+}
+
+// each calls fn with the elements from the top to the bottom until fn returns false
+func (l *LinkedListStack[T]) each(fn func(depth int, e T) bool) {
+	l.Each(fn)
+}
+
+// each calls fn with the elements from the top to the bottom until fn returns false
+func (s *MinMaxStack[T]) each(fn func(depth int, e T) bool) {
+	s.entries.Each(func(depth int, entry minMaxEntry[T]) bool {
+		return fn(depth, entry.val)
+	})
+}
+
+// each calls fn with the elements from the top to the bottom until fn returns false
+func (s *MonotonicStack[T]) each(fn func(depth int, e T) bool) {
+	s.entries.Each(fn)
+}
+
+// each calls fn with the elements from the top to the bottom until fn returns false
+func (s *SegmentedStack[T]) each(fn func(depth int, e T) bool) {
+	depth, n := 0, s.n
+	for seg := s.top; seg != nil; seg = seg.below {
+		low := 0
+		if seg.below == nil {
+			low = s.start
+		}
+		for i := n - 1; i >= low; i-- {
+			if !fn(depth, seg.elements[i]) {
+				return
+			}
+			depth++
+		}
+		n = s.segmentSize
+	}
+}
+
+// each calls fn with the elements from the front to the back until fn returns false
+func (q *MinMaxQueue[T]) each(fn func(index int, e T) bool) {
+	// the front is the top of out, the back is the top of in
+	index := 0
+	stop := false
+	q.out.each(func(_ int, e T) bool {
+		stop = !fn(index, e)
+		index++
+		return !stop
+	})
+	if stop {
+		return
+	}
+	for _, entry := range q.in.entries.elements {
+		if !fn(index, entry.val) {
+			return
+		}
+		index++
+	}
+}
+
+// each calls fn with the elements from the front to the back until fn returns false
+func (q *MonotonicQueue[T]) each(fn func(index int, e T) bool) {
+	for i, e := range q.elements[q.head:] {
+		if !fn(i, e) {
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"cmp"
+	"fmt"
+	"testing"
+
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/stretchr/testify/assert"
+)
+
+// bareStack is a Stack of another package, without Each
+type bareStack[T any] struct {
+	Stack[T]
+}
+
+func TestStack_Format(t *testing.T) {
+	testCases := []struct {
+		name   string
+		stack  Stack[int]
+		goName string
+	}{
+		{name: "array", stack: NewArrayStack[int](), goName: "&stack.ArrayStack[int]"},
+		{name: "linked list", stack: NewLinkedListStack[int](), goName: "&stack.LinkedListStack[int]"},
+		{
+			name:   "linked list over a tree list",
+			stack:  NewLinkedListStackWithList[int](linkedlist.NewTreeList[int]()),
+			goName: "&stack.LinkedListStack[int]",
+		},
+		{name: "min max", stack: NewMinMaxStack[int](cmp.Compare[int]), goName: "&stack.MinMaxStack[int]"},
+		{name: "monotonic", stack: NewMonotonicStack[int](cmp.Compare[int]), goName: "&stack.MonotonicStack[int]"},
+		{name: "segmented", stack: NewSegmentedStack[int](2), goName: "&stack.SegmentedStack[int]"},
+		{name: "concurrent", stack: NewDefaultConcurrentStack[int](), goName: "&stack.ConcurrentStack[int]"},
+		{
+			name:   "concurrent over an extended stack",
+			stack:  NewConcurrentStack[int](ExtendedStack[int](NewLinkedListStack[int]())),
+			goName: "&stack.ConcurrentStack[int]",
+		},
+		{name: "elimination", stack: NewEliminationStack[int](1, 1), goName: "&stack.EliminationStack[int]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, "[]", fmt.Sprint(tc.stack))
+			for i := 1; i <= 5; i++ {
+				tc.stack.Push(i)
+			}
+			assert.Equal(t, "[5 4 3 2 1]", fmt.Sprint(tc.stack))
+			assert.Equal(t, "[5 4 3 2 1]", tc.stack.(fmt.Stringer).String())
+			assert.Equal(t, "[0:5 1:4 2:3 3:2 4:1]", fmt.Sprintf("%+v", tc.stack))
+			assert.Equal(t, tc.goName+"{5, 4, 3, 2, 1}", tc.stack.(fmt.GoStringer).GoString())
+			assert.Equal(t, "[5 4 3 ... (2 more)]", fmt.Sprintf("%.3v", tc.stack))
+			assert.Equal(t, "[... (5 more)]", fmt.Sprintf("%.0v", tc.stack))
+			assert.Equal(t, "[5 4 ... (3 more)]", fmt.Sprintf("%18v", tc.stack))
+			assert.Equal(t, "[5 4 3 2 1]", fmt.Sprintf("%v", tc.stack))
+		})
+	}
+}
+
+func TestConcurrentStack_FormatBare(t *testing.T) {
+	s := NewConcurrentStack[int](bareStack[int]{Stack: NewMinMaxStack[int](cmp.Compare[int])})
+	s.Push(1)
+	s.Push(2)
+	assert.Equal(t, "[... (2 more)]", fmt.Sprint(s))
+}
+
+func TestQueue_Format(t *testing.T) {
+	minMax := NewMinMaxQueue[int](cmp.Compare[int])
+	for i := 1; i <= 3; i++ {
+		minMax.Enqueue(i)
+	}
+	// moves the front elements to the out stack
+	minMax.Dequeue()
+	minMax.Enqueue(4)
+	minMax.Enqueue(5)
+	assert.Equal(t, "[2 3 4 5]", fmt.Sprint(minMax))
+	assert.Equal(t, "[0:2 1:3 2:4 3:5]", fmt.Sprintf("%+v", minMax))
+	assert.Equal(t, "[2 3 ... (2 more)]", fmt.Sprintf("%.2v", minMax))
+	assert.Equal(t, "[2 ... (3 more)]", fmt.Sprintf("%.1v", minMax))
+	assert.Equal(t, "&stack.MinMaxQueue[int]{2, 3, 4, 5}", minMax.GoString())
+
+	monotonic := NewMonotonicQueue[int](cmp.Compare[int])
+	for _, e := range []int{3, 1, 2, 5, 4} {
+		monotonic.Enqueue(e)
+	}
+	monotonic.Dequeue()
+	assert.Equal(t, "[2 4]", monotonic.String())
+	assert.Equal(t, "&stack.MonotonicQueue[int]{2, 4}", monotonic.GoString())
+}
@@ -14,17 +14,54 @@
 
 package stack
 
-import linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+import (
+	"github.com/chenmingyong0423/algorithms/errs"
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/option"
+)
 
-var _ Stack[any] = (*LinkedListStack[any])(nil)
+var _ ExtendedStack[any] = (*LinkedListStack[any])(nil)
 
 type LinkedListStack[T any] struct {
 	list linkedlist.LinkedList[T]
+	// newList returns an empty list for a clone of the stack
+	newList func() linkedlist.LinkedList[T]
 }
 
-func NewLinkedListStack[T any]() *LinkedListStack[T] {
+// NewLinkedListStack returns a new stack configured by opts.
+// The nodes are allocated by the option.Allocator[linkedlist.SinglyNode[T]] set by option.WithAllocator, if any.
+func NewLinkedListStack[T any](opts ...option.Option[T]) *LinkedListStack[T] {
 	return &LinkedListStack[T]{
-		list: linkedlist.NewSinglyLinkedList[T](),
+		list: linkedlist.NewSinglyLinkedListWithOptions[T](opts...),
+		newList: func() linkedlist.LinkedList[T] {
+			// the clones do not share the hooks
+			return linkedlist.NewSinglyLinkedListWithOptions[T](append(opts[:len(opts):len(opts)], option.WithHooks[T](option.Hooks[T]{}))...)
+		},
+	}
+}
+
+// NewLinkedListStackWithList returns a new stack backed by the list, e.g. a linkedlist.TreeList.
+// The top of the stack is the last element of the list.
+func NewLinkedListStackWithList[T any](list linkedlist.LinkedList[T]) *LinkedListStack[T] {
+	return &LinkedListStack[T]{
+		list: list,
+		newList: func() linkedlist.LinkedList[T] {
+			return emptyLike(list)
+		},
+	}
+}
+
+// emptyLike returns an empty list of the same type as list, with the default options
+func emptyLike[T any](list linkedlist.LinkedList[T]) linkedlist.LinkedList[T] {
+	switch list.(type) {
+	case *linkedlist.DoublyLinkedList[T]:
+		return linkedlist.NewDoublyLinkedList[T]()
+	case *linkedlist.TreeList[T]:
+		return linkedlist.NewTreeList[T]()
+	case *linkedlist.ConcurrentLinkedList[T]:
+		return linkedlist.NewDefaultConcurrentLinkedList[T]()
+	default:
+		return linkedlist.NewSinglyLinkedList[T]()
 	}
 }
 
@@ -48,6 +85,155 @@ func (l *LinkedListStack[T]) Size() int {
 	return l.list.Size()
 }
 
+// PushE pushes an element onto the top of the stack
+// If the stack is full and the overflow policy rejects the element, errs.ErrFull is returned,
+// the lists backing the stack which do not have an AddE method never reject it
+func (l *LinkedListStack[T]) PushE(e T) error {
+	if list, ok := l.list.(interface{ AddE(elements ...T) error }); ok {
+		return list.AddE(e)
+	}
+	l.Push(e)
+	return nil
+}
+
+// PopE removes the element at the top of the stack and returns that element
+// If the stack is empty, errs.ErrEmpty is returned
+func (l *LinkedListStack[T]) PopE() (T, error) {
+	t, ok := l.Pop()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// PeekE returns the element at the top of the stack
+// If the stack is empty, errs.ErrEmpty is returned
+func (l *LinkedListStack[T]) PeekE() (T, error) {
+	t, ok := l.Peek()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// Observe registers the hooks called after the stack has been changed
+// and returns a function unregistering them.
+// If the list backing the stack is not linkedlist.Observable, the hooks are never called and cancel does nothing.
+func (l *LinkedListStack[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	observable, ok := l.list.(linkedlist.Observable[T])
+	if !ok {
+		return func() {}
+	}
+	return observable.Observe(hooks)
+}
+
+// PushAll pushes the elements in order, the last one ends up on top
+// If the elements do not fit, the overflow policy of the list is applied to all of them at once,
+// e.g. OverflowReject rejects all of them
+func (l *LinkedListStack[T]) PushAll(elements ...T) {
+	l.list.Add(elements...)
+}
+
+// PopN removes the n elements at the top of the stack, or all of them if there are fewer,
+// and returns them from the top
+func (l *LinkedListStack[T]) PopN(n int) []T {
+	n = min(max(n, 0), l.Size())
+	elements := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		e, _ := l.Pop()
+		elements = append(elements, e)
+	}
+	return elements
+}
+
+// PeekN returns the n elements at the top of the stack, or all of them if there are fewer, from the top
+func (l *LinkedListStack[T]) PeekN(n int) []T {
+	n = min(max(n, 0), l.Size())
+	return l.Values()[:n]
+}
+
+// PeekAt returns the element at the depth, the top element is at depth 0
+// If there is no element at the depth, b return false
+func (l *LinkedListStack[T]) PeekAt(depth int) (t T, b bool) {
+	if depth < 0 {
+		return
+	}
+	return l.list.Get(l.Size() - 1 - depth)
+}
+
+// Clear removes all the elements of the stack
+func (l *LinkedListStack[T]) Clear() {
+	l.list.Clear()
+}
+
+// Values returns the elements of the stack from the top to the bottom
+func (l *LinkedListStack[T]) Values() []T {
+	values := l.list.Values()
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+	return values
+}
+
+// Each calls fn with the elements from the top to the bottom until fn returns false
+func (l *LinkedListStack[T]) Each(fn func(depth int, e T) bool) {
+	for depth, e := range l.Values() {
+		if !fn(depth, e) {
+			return
+		}
+	}
+}
+
+// Clone returns a copy of the stack without the observers.
+// The clone of a stack built by NewLinkedListStackWithList uses a list of the same type with the default options.
+func (l *LinkedListStack[T]) Clone() *LinkedListStack[T] {
+	list := l.newList()
+	list.Add(l.list.Values()...)
+	return &LinkedListStack[T]{
+		list:    list,
+		newList: l.newList,
+	}
+}
+
+// Swap exchanges the two elements at the top of the stack, like SWAP in Forth
+// If there are fewer than two elements, b return false
+func (l *LinkedListStack[T]) Swap() bool {
+	return l.Rotate(2)
+}
+
+// Dup pushes a copy of the element at the top of the stack, like DUP in Forth
+// If the stack is empty or full, b return false
+func (l *LinkedListStack[T]) Dup() bool {
+	top, ok := l.Peek()
+	if !ok {
+		return false
+	}
+	return l.PushE(top) == nil
+}
+
+// Rotate moves the element at depth n-1 to the top of the stack, like n-1 ROLL in Forth, e.g. Rotate(3) is ROT (2 ROLL).
+// A negative n moves the top element to depth -n-1 instead, e.g. Rotate(-3) is -ROT.
+// The observers are notified of the element removed and added back.
+// If there are fewer than |n| elements, b return false
+func (l *LinkedListStack[T]) Rotate(n int) bool {
+	k, size := abs(n), l.Size()
+	switch {
+	case k > size:
+		return false
+	case k < 2:
+		return true
+	// rotating two elements either way swaps them
+	case n > 0 || k == 2:
+		e, _ := l.list.Remove(size - k)
+		l.list.Add(e)
+	default:
+		// the index is below size-2, where Insert does not append
+		e, _ := l.list.RemoveLast()
+		l.list.Insert(size-k, e)
+	}
+	return true
+}
+
 func (l *LinkedListStack[T]) toSlice() (elements []T) {
 	return l.list.Values()
 }
@@ -17,6 +17,9 @@ package stack
 import (
 	"testing"
 
+	"github.com/chenmingyong0423/algorithms/errs"
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/option"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -222,3 +225,143 @@ func TestLinkedList_Size(t *testing.T) {
 		})
 	}
 }
+
+func TestNewLinkedListStack_WithOptions(t *testing.T) {
+	var removed []int
+	s := NewLinkedListStack[int](
+		option.WithMaxSize[int](2),
+		option.WithAllocator[int](option.NewPoolAllocator[linkedlist.SinglyNode[int]]()),
+		option.WithHooks[int](option.Hooks[int]{
+			OnRemove: func(index int, e int) {
+				removed = append(removed, e)
+			},
+		}),
+	)
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+	assert.Equal(t, []int{1, 2}, s.toSlice())
+	s.Pop()
+	assert.Equal(t, []int{2}, removed)
+}
+
+func TestLinkedListStack_Observe(t *testing.T) {
+	var added, removed []int
+	s := NewLinkedListStack[int]()
+	cancel := s.Observe(option.Hooks[int]{
+		OnAdd: func(index int, e int) {
+			added = append(added, index)
+		},
+		OnRemove: func(index int, e int) {
+			removed = append(removed, index)
+		},
+	})
+	s.Push(1)
+	s.Push(2)
+	s.Pop()
+	cancel()
+	s.Push(3)
+	assert.Equal(t, []int{0, 1}, added)
+	assert.Equal(t, []int{1}, removed)
+}
+
+// plainList hides the Observe method of the list it wraps
+type plainList[T any] struct {
+	linkedlist.LinkedList[T]
+}
+
+func TestLinkedListStack_ObserveNotObservable(t *testing.T) {
+	s := NewLinkedListStackWithList[int](plainList[int]{linkedlist.NewSinglyLinkedList[int]()})
+	called := false
+	cancel := s.Observe(option.Hooks[int]{
+		OnAdd: func(index int, e int) {
+			called = true
+		},
+	})
+	s.Push(1)
+	cancel()
+	assert.False(t, called)
+	assert.Equal(t, []int{1}, s.Values())
+}
+
+func TestLinkedListStack_ErrorVariants(t *testing.T) {
+	s := NewLinkedListStack[int](option.WithMaxSize[int](1))
+	_, err := s.PopE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = s.PeekE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	assert.NoError(t, s.PushE(1))
+	assert.Equal(t, errs.ErrFull, s.PushE(2))
+	v, err := s.PeekE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = s.PopE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestLinkedListStack_PushEEvictOldest(t *testing.T) {
+	var evicted []int
+	s := NewLinkedListStack[int](
+		option.WithMaxSize[int](2),
+		option.WithOverflowPolicy[int](option.OverflowEvictOldest),
+		option.WithOnEvict[int](func(elements []int) {
+			evicted = append(evicted, elements...)
+		}),
+	)
+	for i := 1; i <= 4; i++ {
+		assert.NoError(t, s.PushE(i))
+	}
+	assert.Equal(t, []int{4, 3}, s.Values())
+	assert.Equal(t, []int{1, 2}, evicted)
+
+	s = NewLinkedListStackWithList[int](linkedlist.NewTreeListWithOptions[int](
+		option.WithMaxSize[int](1),
+		option.WithOverflowPolicy[int](option.OverflowEvictOldest),
+	))
+	assert.NoError(t, s.PushE(1))
+	assert.NoError(t, s.PushE(2))
+	assert.Equal(t, []int{2}, s.Values())
+}
+
+func TestNewLinkedListStackWithList(t *testing.T) {
+	s := NewLinkedListStackWithList[int](linkedlist.NewTreeList[int](1, 2))
+	s.Push(3)
+	v, ok := s.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	v, ok = s.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 2, s.Size())
+}
+
+func TestLinkedListStack_Clone(t *testing.T) {
+	var added int
+	s := NewLinkedListStack[int](
+		option.WithMaxSize[int](3),
+		option.WithHooks[int](option.Hooks[int]{
+			OnAdd: func(index int, e int) {
+				added++
+			},
+		}),
+	)
+	// the whole PushAll is rejected when the elements do not fit
+	s.PushAll(1, 2, 3, 4)
+	assert.Empty(t, s.toSlice())
+	s.PushAll(1, 2, 3)
+	assert.Equal(t, []int{1, 2, 3}, s.toSlice())
+	assert.False(t, s.Dup())
+	clone := s.Clone()
+	clone.Pop()
+	clone.Push(5)
+	clone.Push(6)
+	assert.Equal(t, []int{1, 2, 5}, clone.toSlice())
+	assert.Equal(t, []int{1, 2, 3}, s.toSlice())
+	assert.Equal(t, 3, added)
+
+	s = NewLinkedListStackWithList[int](linkedlist.NewDoublyLinkedList[int](1, 2))
+	clone = s.Clone()
+	assert.IsType(t, &linkedlist.DoublyLinkedList[int]{}, clone.list)
+	assert.Equal(t, []int{2, 1}, clone.Values())
+}
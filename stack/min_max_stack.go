@@ -0,0 +1,175 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+var _ Stack[any] = (*MinMaxStack[any])(nil)
+
+// minMaxEntry is an element of a MinMaxStack with the extremes of the elements below it
+type minMaxEntry[T any] struct {
+	val T
+	min T
+	max T
+}
+
+// MinMaxStack is a stack returning its minimum and maximum elements in O(1).
+type MinMaxStack[T any] struct {
+	entries *ArrayStack[minMaxEntry[T]]
+	compare func(a, b T) int
+}
+
+// NewMinMaxStack returns a new stack ordering the elements with compare,
+// which returns a negative number if a < b, zero if a == b and a positive number if a > b, e.g. cmp.Compare.
+func NewMinMaxStack[T any](compare func(a, b T) int) *MinMaxStack[T] {
+	return &MinMaxStack[T]{
+		entries: NewArrayStack[minMaxEntry[T]](),
+		compare: compare,
+	}
+}
+
+// Push pushes an element onto the top of the stack
+func (s *MinMaxStack[T]) Push(e T) {
+	entry := minMaxEntry[T]{val: e, min: e, max: e}
+	if top, ok := s.entries.Peek(); ok {
+		if s.compare(top.min, e) < 0 {
+			entry.min = top.min
+		}
+		if s.compare(top.max, e) > 0 {
+			entry.max = top.max
+		}
+	}
+	s.entries.Push(entry)
+}
+
+// Pop removes the element at the top of the stack and returns that element
+func (s *MinMaxStack[T]) Pop() (t T, b bool) {
+	entry, ok := s.entries.Pop()
+	return entry.val, ok
+}
+
+// Peek returns the element at the top of the stack
+func (s *MinMaxStack[T]) Peek() (t T, b bool) {
+	entry, ok := s.entries.Peek()
+	return entry.val, ok
+}
+
+// Min returns the minimum element of the stack
+// If the stack is empty, b return false
+func (s *MinMaxStack[T]) Min() (t T, b bool) {
+	entry, ok := s.entries.Peek()
+	return entry.min, ok
+}
+
+// Max returns the maximum element of the stack
+// If the stack is empty, b return false
+func (s *MinMaxStack[T]) Max() (t T, b bool) {
+	entry, ok := s.entries.Peek()
+	return entry.max, ok
+}
+
+// IsEmpty checks whether the stack is empty
+func (s *MinMaxStack[T]) IsEmpty() bool {
+	return s.entries.IsEmpty()
+}
+
+// Size returns the size of the stack
+func (s *MinMaxStack[T]) Size() int {
+	return s.entries.Size()
+}
+
+// MinMaxQueue is a FIFO queue returning its minimum and maximum elements in O(1),
+// made of two MinMaxStack: the elements are pushed onto the first one
+// and moved to the second one when it is empty, reversing their order.
+// It is typically used to get the extremes of a sliding window.
+type MinMaxQueue[T any] struct {
+	in      *MinMaxStack[T]
+	out     *MinMaxStack[T]
+	compare func(a, b T) int
+}
+
+// NewMinMaxQueue returns a new queue ordering the elements with compare, see NewMinMaxStack.
+func NewMinMaxQueue[T any](compare func(a, b T) int) *MinMaxQueue[T] {
+	return &MinMaxQueue[T]{
+		in:      NewMinMaxStack[T](compare),
+		out:     NewMinMaxStack[T](compare),
+		compare: compare,
+	}
+}
+
+// Enqueue adds an element to the back of the queue
+func (q *MinMaxQueue[T]) Enqueue(e T) {
+	q.in.Push(e)
+}
+
+// Dequeue removes the element at the front of the queue and returns that element, in amortized O(1)
+// If the queue is empty, b return false
+func (q *MinMaxQueue[T]) Dequeue() (t T, b bool) {
+	q.transfer()
+	return q.out.Pop()
+}
+
+// Peek returns the element at the front of the queue, in amortized O(1)
+// If the queue is empty, b return false
+func (q *MinMaxQueue[T]) Peek() (t T, b bool) {
+	q.transfer()
+	return q.out.Peek()
+}
+
+// Min returns the minimum element of the queue
+// If the queue is empty, b return false
+func (q *MinMaxQueue[T]) Min() (t T, b bool) {
+	return q.extreme((*MinMaxStack[T]).Min, -1)
+}
+
+// Max returns the maximum element of the queue
+// If the queue is empty, b return false
+func (q *MinMaxQueue[T]) Max() (t T, b bool) {
+	return q.extreme((*MinMaxStack[T]).Max, 1)
+}
+
+// IsEmpty checks whether the queue is empty
+func (q *MinMaxQueue[T]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+// Size returns the size of the queue
+func (q *MinMaxQueue[T]) Size() int {
+	return q.in.Size() + q.out.Size()
+}
+
+// transfer moves the elements of in to out if out is empty
+func (q *MinMaxQueue[T]) transfer() {
+	if !q.out.IsEmpty() {
+		return
+	}
+	for e, ok := q.in.Pop(); ok; e, ok = q.in.Pop() {
+		q.out.Push(e)
+	}
+}
+
+// extreme combines the extremes of both stacks, sign is -1 for the minimum and 1 for the maximum
+func (q *MinMaxQueue[T]) extreme(get func(s *MinMaxStack[T]) (T, bool), sign int) (T, bool) {
+	in, inOk := get(q.in)
+	out, outOk := get(q.out)
+	switch {
+	case !inOk:
+		return out, outOk
+	case !outOk:
+		return in, inOk
+	case q.compare(in, out)*sign > 0:
+		return in, true
+	default:
+		return out, true
+	}
+}
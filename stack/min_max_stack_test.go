@@ -0,0 +1,117 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinMaxStack(t *testing.T) {
+	testCases := []struct {
+		name     string
+		elements []int
+		pop      int
+
+		wantMin  int
+		wantMax  int
+		wantBool bool
+	}{
+		{
+			name:     "empty stack",
+			wantBool: false,
+		},
+		{
+			name:     "one element",
+			elements: []int{1},
+			wantMin:  1,
+			wantMax:  1,
+			wantBool: true,
+		},
+		{
+			name:     "multiple elements",
+			elements: []int{3, 1, 4, 1, 5, 9, 2, 6},
+			wantMin:  1,
+			wantMax:  9,
+			wantBool: true,
+		},
+		{
+			name:     "extremes popped",
+			elements: []int{3, 4, 1, 5, 9, 2, 6},
+			pop:      5,
+			wantMin:  3,
+			wantMax:  4,
+			wantBool: true,
+		},
+		{
+			name:     "all popped",
+			elements: []int{3, 4},
+			pop:      2,
+			wantBool: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewMinMaxStack[int](cmp.Compare[int])
+			for _, e := range tc.elements {
+				s.Push(e)
+			}
+			for i := 0; i < tc.pop; i++ {
+				_, ok := s.Pop()
+				assert.True(t, ok)
+			}
+			assert.Equal(t, len(tc.elements)-tc.pop, s.Size())
+			gotMin, ok := s.Min()
+			assert.Equal(t, tc.wantBool, ok)
+			assert.Equal(t, tc.wantMin, gotMin)
+			gotMax, ok := s.Max()
+			assert.Equal(t, tc.wantBool, ok)
+			assert.Equal(t, tc.wantMax, gotMax)
+		})
+	}
+}
+
+func TestMinMaxQueue(t *testing.T) {
+	// the extremes of the sliding windows of size 3
+	elements := []int{1, 3, -1, -3, 5, 3, 6, 7}
+	wantMin := []int{-1, -3, -3, -3, 3, 3}
+	wantMax := []int{3, 3, 5, 5, 6, 7}
+
+	q := NewMinMaxQueue[int](cmp.Compare[int])
+	_, ok := q.Min()
+	assert.False(t, ok)
+	var gotMin, gotMax []int
+	for i, e := range elements {
+		q.Enqueue(e)
+		if q.Size() > 3 {
+			front, ok := q.Dequeue()
+			assert.True(t, ok)
+			assert.Equal(t, elements[i-3], front)
+		}
+		if q.Size() == 3 {
+			minimum, _ := q.Min()
+			maximum, _ := q.Max()
+			gotMin, gotMax = append(gotMin, minimum), append(gotMax, maximum)
+		}
+	}
+	assert.Equal(t, wantMin, gotMin)
+	assert.Equal(t, wantMax, gotMax)
+	front, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 3, front)
+	assert.False(t, q.IsEmpty())
+}
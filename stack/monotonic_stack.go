@@ -0,0 +1,289 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import "cmp"
+
+var _ Stack[any] = (*MonotonicStack[any])(nil)
+
+// MonotonicStack is a stack keeping its elements ordered from the bottom to the top:
+// pushing an element first pops the elements on top which would break the order.
+type MonotonicStack[T any] struct {
+	entries *ArrayStack[T]
+	compare func(a, b T) int
+	strict  bool
+}
+
+// NewMonotonicStack returns a new stack whose elements are non-decreasing from the bottom to the top,
+// ordered with compare, which returns a negative number if a < b, zero if a == b and a positive number if a > b,
+// e.g. cmp.Compare. Reversing compare makes the stack non-increasing.
+func NewMonotonicStack[T any](compare func(a, b T) int) *MonotonicStack[T] {
+	return &MonotonicStack[T]{
+		entries: NewArrayStack[T](),
+		compare: compare,
+	}
+}
+
+// NewStrictMonotonicStack returns a new stack whose elements are increasing from the bottom to the top,
+// pushing an element also pops the elements equal to it, see NewMonotonicStack.
+func NewStrictMonotonicStack[T any](compare func(a, b T) int) *MonotonicStack[T] {
+	s := NewMonotonicStack(compare)
+	s.strict = true
+	return s
+}
+
+// Push pops the elements breaking the order and pushes an element onto the top of the stack
+func (s *MonotonicStack[T]) Push(e T) {
+	s.PushFunc(e, nil)
+}
+
+// PushFunc is like Push and calls popped with each popped element, from the top
+func (s *MonotonicStack[T]) PushFunc(e T, popped func(e T)) {
+	for top, ok := s.entries.Peek(); ok && s.breaks(top, e); top, ok = s.entries.Peek() {
+		s.entries.Pop()
+		if popped != nil {
+			popped(top)
+		}
+	}
+	s.entries.Push(e)
+}
+
+// Pop removes the element at the top of the stack and returns that element
+func (s *MonotonicStack[T]) Pop() (t T, b bool) {
+	return s.entries.Pop()
+}
+
+// Peek returns the element at the top of the stack
+func (s *MonotonicStack[T]) Peek() (t T, b bool) {
+	return s.entries.Peek()
+}
+
+// IsEmpty checks whether the stack is empty
+func (s *MonotonicStack[T]) IsEmpty() bool {
+	return s.entries.IsEmpty()
+}
+
+// Size returns the size of the stack
+func (s *MonotonicStack[T]) Size() int {
+	return s.entries.Size()
+}
+
+// breaks checks whether e cannot be pushed on top of top
+func (s *MonotonicStack[T]) breaks(top, e T) bool {
+	c := s.compare(top, e)
+	return c > 0 || s.strict && c == 0
+}
+
+// MonotonicQueue is a FIFO queue keeping its elements ordered from the front to the back:
+// enqueuing an element first removes the elements at the back which would break the order.
+// The front is then the minimum of the queue, or the maximum with a reversed order.
+type MonotonicQueue[T any] struct {
+	elements []T
+	head     int
+	compare  func(a, b T) int
+}
+
+// NewMonotonicQueue returns a new queue whose elements are non-decreasing from the front to the back,
+// ordered with compare, see NewMonotonicStack.
+func NewMonotonicQueue[T any](compare func(a, b T) int) *MonotonicQueue[T] {
+	return &MonotonicQueue[T]{
+		compare: compare,
+	}
+}
+
+// Enqueue removes the elements breaking the order and adds an element to the back of the queue
+func (q *MonotonicQueue[T]) Enqueue(e T) {
+	for len(q.elements) > q.head && q.compare(q.elements[len(q.elements)-1], e) > 0 {
+		q.elements = q.elements[:len(q.elements)-1]
+	}
+	q.elements = append(q.elements, e)
+}
+
+// Dequeue removes the element at the front of the queue and returns that element, in amortized O(1)
+// If the queue is empty, b return false
+func (q *MonotonicQueue[T]) Dequeue() (t T, b bool) {
+	if q.IsEmpty() {
+		return
+	}
+	t = q.elements[q.head]
+	var zero T
+	q.elements[q.head] = zero
+	q.head++
+	// the removed front is reclaimed once it makes up half of the slice
+	if q.head*2 >= len(q.elements) {
+		n := copy(q.elements, q.elements[q.head:])
+		clear(q.elements[n:])
+		q.elements = q.elements[:n]
+		q.head = 0
+	}
+	return t, true
+}
+
+// Peek returns the element at the front of the queue
+// If the queue is empty, b return false
+func (q *MonotonicQueue[T]) Peek() (t T, b bool) {
+	if q.IsEmpty() {
+		return
+	}
+	return q.elements[q.head], true
+}
+
+// IsEmpty checks whether the queue is empty
+func (q *MonotonicQueue[T]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+// Size returns the size of the queue
+func (q *MonotonicQueue[T]) Size() int {
+	return len(q.elements) - q.head
+}
+
+// Number is the constraint of the numeric types.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// indexed is an element with its index, ordered by value
+type indexed[T any] struct {
+	index int
+	value T
+}
+
+func compareValues[T cmp.Ordered](a, b indexed[T]) int {
+	return cmp.Compare(a.value, b.value)
+}
+
+func compareValuesReversed[T cmp.Ordered](a, b indexed[T]) int {
+	return cmp.Compare(b.value, a.value)
+}
+
+// NextGreater returns for each element the index of the next element greater than it, or -1 if there is none.
+func NextGreater[T cmp.Ordered](values []T) []int {
+	result := make([]int, len(values))
+	// the waiting elements are non-increasing, a greater element pops the ones it answers
+	s := NewMonotonicStack(compareValuesReversed[T])
+	for i, v := range values {
+		s.PushFunc(indexed[T]{index: i, value: v}, func(e indexed[T]) {
+			result[e.index] = i
+		})
+	}
+	for e, ok := s.Pop(); ok; e, ok = s.Pop() {
+		result[e.index] = -1
+	}
+	return result
+}
+
+// PrevSmaller returns for each element the index of the previous element smaller than it, or -1 if there is none.
+func PrevSmaller[T cmp.Ordered](values []T) []int {
+	result := make([]int, len(values))
+	// walking backwards, the waiting elements are non-decreasing, a smaller element pops the ones it answers
+	s := NewMonotonicStack(compareValues[T])
+	for i := len(values) - 1; i >= 0; i-- {
+		s.PushFunc(indexed[T]{index: i, value: values[i]}, func(e indexed[T]) {
+			result[e.index] = i
+		})
+	}
+	for e, ok := s.Pop(); ok; e, ok = s.Pop() {
+		result[e.index] = -1
+	}
+	return result
+}
+
+// StockSpan returns for each price the number of consecutive prices up to it, itself included,
+// which are lower than or equal to it.
+func StockSpan[T cmp.Ordered](prices []T) []int {
+	result := make([]int, len(prices))
+	// walking backwards, a price pops the lower waiting prices, it is the previous greater price of them
+	s := NewMonotonicStack(compareValuesReversed[T])
+	for i := len(prices) - 1; i >= 0; i-- {
+		s.PushFunc(indexed[T]{index: i, value: prices[i]}, func(e indexed[T]) {
+			result[e.index] = e.index - i
+		})
+	}
+	for e, ok := s.Pop(); ok; e, ok = s.Pop() {
+		result[e.index] = e.index + 1
+	}
+	return result
+}
+
+// LargestRectangle returns the area of the largest rectangle in the histogram of heights, each bar having a width of 1,
+// and the bars [from, to) it spans.
+func LargestRectangle[T Number](heights []T) (area T, from, to int) {
+	// the bars are non-decreasing, a lower bar closes the rectangles of the higher bars it pops:
+	// a popped bar extends from the bar below it on the stack to the lower bar
+	s := NewMonotonicStack(compareValues[T])
+	closeRectangle := func(end int) func(e indexed[T]) {
+		return func(e indexed[T]) {
+			start := 0
+			if below, ok := s.Peek(); ok {
+				start = below.index + 1
+			}
+			if a := e.value * T(end-start); a > area {
+				area, from, to = a, start, end
+			}
+		}
+	}
+	for i, h := range heights {
+		s.PushFunc(indexed[T]{index: i, value: h}, closeRectangle(i))
+	}
+	closeAtEnd := closeRectangle(len(heights))
+	for e, ok := s.Pop(); ok; e, ok = s.Pop() {
+		closeAtEnd(e)
+	}
+	return area, from, to
+}
+
+// SlidingWindowMax returns the maximum of each window of k consecutive values, from left to right.
+// It returns nil if k is not positive or greater than the number of values.
+func SlidingWindowMax[T cmp.Ordered](values []T, k int) []T {
+	if k <= 0 || k > len(values) {
+		return nil
+	}
+	result := make([]T, 0, len(values)-k+1)
+	// the candidates are non-increasing, the front is the maximum of the window
+	q := NewMonotonicQueue(compareValuesReversed[T])
+	for i, v := range values {
+		q.Enqueue(indexed[T]{index: i, value: v})
+		if front, _ := q.Peek(); front.index <= i-k {
+			q.Dequeue()
+		}
+		if i >= k-1 {
+			front, _ := q.Peek()
+			result = append(result, front.value)
+		}
+	}
+	return result
+}
+
+// TrappingRainWater returns the amount of water trapped between the bars of the elevation map heights,
+// each bar having a width of 1.
+func TrappingRainWater[T Number](heights []T) T {
+	var water T
+	// the bars are non-increasing, a higher bar closes the pools above the lower bars it pops:
+	// a pool lies between the bar below the popped one and the higher bar, above the popped bar
+	s := NewMonotonicStack(compareValuesReversed[T])
+	for i, h := range heights {
+		s.PushFunc(indexed[T]{index: i, value: h}, func(e indexed[T]) {
+			left, ok := s.Peek()
+			if !ok {
+				return
+			}
+			water += (min(left.value, h) - e.value) * T(i-left.index-1)
+		})
+	}
+	return water
+}
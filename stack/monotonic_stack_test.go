@@ -0,0 +1,209 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"cmp"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonotonicStack(t *testing.T) {
+	testCases := []struct {
+		name       string
+		stack      *MonotonicStack[int]
+		elements   []int
+		wantPopped []int
+		wantValues []int
+	}{
+		{
+			name:       "non-decreasing",
+			stack:      NewMonotonicStack[int](cmp.Compare[int]),
+			elements:   []int{1, 3, 3, 2, 5},
+			wantPopped: []int{3, 3},
+			wantValues: []int{5, 2, 1},
+		},
+		{
+			name:       "increasing",
+			stack:      NewStrictMonotonicStack[int](cmp.Compare[int]),
+			elements:   []int{1, 3, 3, 2, 5},
+			wantPopped: []int{3, 3},
+			wantValues: []int{5, 2, 1},
+		},
+		{
+			name:       "non-increasing",
+			stack:      NewMonotonicStack[int](func(a, b int) int { return cmp.Compare(b, a) }),
+			elements:   []int{5, 3, 3, 4, 1},
+			wantPopped: []int{3, 3},
+			wantValues: []int{1, 4, 5},
+		},
+		{
+			name:       "strict keeps no duplicates",
+			stack:      NewStrictMonotonicStack[int](cmp.Compare[int]),
+			elements:   []int{2, 2, 2},
+			wantPopped: []int{2, 2},
+			wantValues: []int{2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var popped []int
+			for _, e := range tc.elements {
+				tc.stack.PushFunc(e, func(e int) {
+					popped = append(popped, e)
+				})
+			}
+			assert.Equal(t, tc.wantPopped, popped)
+			assert.Equal(t, len(tc.wantValues), tc.stack.Size())
+			top, ok := tc.stack.Peek()
+			assert.True(t, ok)
+			assert.Equal(t, tc.wantValues[0], top)
+			var values []int
+			for e, ok := tc.stack.Pop(); ok; e, ok = tc.stack.Pop() {
+				values = append(values, e)
+			}
+			assert.Equal(t, tc.wantValues, values)
+			assert.True(t, tc.stack.IsEmpty())
+		})
+	}
+}
+
+func TestMonotonicQueue(t *testing.T) {
+	q := NewMonotonicQueue[int](cmp.Compare[int])
+	_, ok := q.Dequeue()
+	assert.False(t, ok)
+	_, ok = q.Peek()
+	assert.False(t, ok)
+	for _, e := range []int{3, 1, 4, 1, 5} {
+		q.Enqueue(e)
+	}
+	// 3 and 4 are removed by the 1 following them
+	assert.Equal(t, 3, q.Size())
+	v, ok := q.Dequeue()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, ok = q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	q.Enqueue(9)
+	q.Enqueue(2)
+	assert.Equal(t, 2, q.Size())
+	v, _ = q.Dequeue()
+	assert.Equal(t, 1, v)
+	v, _ = q.Dequeue()
+	assert.Equal(t, 2, v)
+	assert.True(t, q.IsEmpty())
+}
+
+func TestMonotonicAlgorithms(t *testing.T) {
+	values := []int{2, 1, 2, 4, 3, 1, 5}
+	assert.Equal(t, []int{3, 2, 3, 6, 6, 6, -1}, NextGreater(values))
+	assert.Equal(t, []int{-1, -1, 1, 2, 2, -1, 5}, PrevSmaller(values))
+	assert.Equal(t, []int{1, 1, 3, 4, 1, 1, 7}, StockSpan(values))
+	assert.Equal(t, []int{2, 4, 4, 4, 5}, SlidingWindowMax(values, 3))
+	assert.Nil(t, SlidingWindowMax(values, 0))
+	assert.Nil(t, SlidingWindowMax(values, 8))
+
+	area, from, to := LargestRectangle([]int{2, 1, 5, 6, 2, 3})
+	assert.Equal(t, 10, area)
+	assert.Equal(t, 2, from)
+	assert.Equal(t, 4, to)
+	area, _, _ = LargestRectangle([]int{})
+	assert.Equal(t, 0, area)
+
+	assert.Equal(t, 6, TrappingRainWater([]int{0, 1, 0, 2, 1, 0, 1, 3, 2, 1, 2, 1}))
+	assert.Equal(t, uint(9), TrappingRainWater([]uint{4, 2, 0, 3, 2, 5}))
+	assert.Equal(t, 0.5, TrappingRainWater([]float64{1, 0.5, 1}))
+}
+
+func TestMonotonicAlgorithms_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		values := make([]int, r.Intn(30))
+		for i := range values {
+			values[i] = r.Intn(6)
+		}
+		nextGreater := NextGreater(values)
+		prevSmaller := PrevSmaller(values)
+		stockSpan := StockSpan(values)
+		var water, area int
+		for i, v := range values {
+			want := -1
+			for j := i + 1; j < len(values); j++ {
+				if values[j] > v {
+					want = j
+					break
+				}
+			}
+			assert.Equal(t, want, nextGreater[i])
+
+			want = -1
+			for j := i - 1; j >= 0; j-- {
+				if values[j] < v {
+					want = j
+					break
+				}
+			}
+			assert.Equal(t, want, prevSmaller[i])
+
+			span := 1
+			for j := i - 1; j >= 0 && values[j] <= v; j-- {
+				span++
+			}
+			assert.Equal(t, span, stockSpan[i])
+
+			left, right := 0, 0
+			for j := 0; j <= i; j++ {
+				left = max(left, values[j])
+			}
+			for j := i; j < len(values); j++ {
+				right = max(right, values[j])
+			}
+			water += min(left, right) - v
+
+			low := v
+			for j := i; j < len(values); j++ {
+				low = min(low, values[j])
+				area = max(area, low*(j-i+1))
+			}
+		}
+		assert.Equal(t, water, TrappingRainWater(values))
+		gotArea, from, to := LargestRectangle(values)
+		assert.Equal(t, area, gotArea)
+		if gotArea > 0 {
+			low := values[from]
+			for _, v := range values[from:to] {
+				low = min(low, v)
+			}
+			assert.Equal(t, gotArea, low*(to-from))
+		}
+
+		k := r.Intn(len(values)+1) + 1
+		windows := SlidingWindowMax(values, k)
+		if k > len(values) {
+			assert.Nil(t, windows)
+			continue
+		}
+		for i, got := range windows {
+			want := values[i]
+			for _, v := range values[i : i+k] {
+				want = max(want, v)
+			}
+			assert.Equal(t, want, got)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+)
+
+var _ Stack[any] = (*SegmentedStack[any])(nil)
+
+// defaultSegmentSize is the number of elements of a segment if none is given
+const defaultSegmentSize = 1024
+
+// segment is a fixed-size chunk of a SegmentedStack, linked to the segment below it
+type segment[T any] struct {
+	elements []T
+	below    *segment[T]
+}
+
+// SegmentedStack is a stack made of fixed-size segments linked together.
+// Unlike ArrayStack, it never copies its elements to grow, so Push and Pop are O(1) in the worst case,
+// and it releases the segments emptied by Pop.
+type SegmentedStack[T any] struct {
+	top *segment[T]
+	// n is the number of elements in the top segment, the free slots of the bottom one included
+	n int
+	// start is the index of the bottom element in the bottom segment, the slots below it were evicted
+	start int
+	// spare is the last segment emptied, kept to avoid reallocating when pushing and popping across a boundary
+	spare       *segment[T]
+	segmentSize int
+	size        int
+
+	maxSize   int
+	overflow  option.OverflowPolicy
+	onEvict   func(elements []T)
+	observers option.Observers[T]
+}
+
+// NewSegmentedStack returns a new stack made of segments of segmentSize elements, configured by opts.
+// If segmentSize is not positive, segments of 1024 elements are used.
+// The overflow policy is applied as by ArrayStack, see NewArrayStack.
+func NewSegmentedStack[T any](segmentSize int, opts ...option.Option[T]) *SegmentedStack[T] {
+	if segmentSize <= 0 {
+		segmentSize = defaultSegmentSize
+	}
+	o := option.Apply(opts...)
+	return &SegmentedStack[T]{
+		segmentSize: segmentSize,
+		maxSize:     o.MaxSize,
+		overflow:    o.Overflow,
+		onEvict:     o.OnEvict,
+		observers:   option.NewObservers(o.Hooks),
+	}
+}
+
+// Push pushes an element onto the top of the stack
+// If the stack is full, the overflow policy is applied, see PushE
+func (s *SegmentedStack[T]) Push(e T) {
+	s.push(e)
+}
+
+// push pushes an element onto the top of the stack after applying the overflow policy
+// If the element is rejected, b return false
+func (s *SegmentedStack[T]) push(e T) bool {
+	if s.maxSize > 0 && s.size >= s.maxSize {
+		switch s.overflow {
+		case option.OverflowEvictOldest:
+			s.evict(s.removeBottom())
+		case option.OverflowEvictNewest:
+			top, _ := s.Pop()
+			s.evict(top)
+		default:
+			s.evict(e)
+			return false
+		}
+	}
+	if s.top == nil || s.n == s.segmentSize {
+		seg := s.spare
+		if seg == nil {
+			seg = &segment[T]{elements: make([]T, s.segmentSize)}
+		}
+		s.spare = nil
+		seg.below = s.top
+		s.top, s.n = seg, 0
+	}
+	s.top.elements[s.n] = e
+	s.n++
+	s.size++
+	s.observers.NotifyAdd(s.size-1, e)
+	return true
+}
+
+// removeBottom removes the element at the bottom of the stack, which must not be empty,
+// in O(n/segmentSize) to find the bottom segment
+func (s *SegmentedStack[T]) removeBottom() T {
+	var above *segment[T]
+	seg := s.top
+	for seg.below != nil {
+		above, seg = seg, seg.below
+	}
+	bottom := seg.elements[s.start]
+	var zero T
+	seg.elements[s.start] = zero
+	s.start++
+	s.size--
+	if s.size == 0 {
+		s.top, s.n, s.start = nil, 0, 0
+		s.spare = seg
+	} else if s.start == s.segmentSize {
+		// the bottom segment is empty, the one above it becomes the bottom
+		above.below = nil
+		s.start = 0
+		if s.spare == nil {
+			s.spare = seg
+		}
+	}
+	s.observers.NotifyRemove(0, bottom)
+	return bottom
+}
+
+// evict passes the elements dropped or rejected because of the overflow policy to onEvict
+func (s *SegmentedStack[T]) evict(elements ...T) {
+	if s.onEvict != nil {
+		s.onEvict(elements)
+	}
+}
+
+// Pop removes the element at the top of the stack and returns that element
+func (s *SegmentedStack[T]) Pop() (t T, b bool) {
+	if s.size == 0 {
+		return
+	}
+	s.n--
+	t = s.top.elements[s.n]
+	// the vacated slot is zeroed so that the element can be garbage collected
+	var zero T
+	s.top.elements[s.n] = zero
+	s.size--
+	if s.n == 0 || s.size == 0 {
+		// the empty segment replaces the spare one, which is released
+		seg := s.top
+		s.top = seg.below
+		seg.below = nil
+		s.spare = seg
+		if s.top != nil {
+			s.n = s.segmentSize
+		} else {
+			s.n, s.start = 0, 0
+		}
+	}
+	s.observers.NotifyRemove(s.size, t)
+	return t, true
+}
+
+// Peek returns the element at the top of the stack
+func (s *SegmentedStack[T]) Peek() (t T, b bool) {
+	if s.size == 0 {
+		return
+	}
+	return s.top.elements[s.n-1], true
+}
+
+// IsEmpty checks whether the stack is empty
+func (s *SegmentedStack[T]) IsEmpty() bool {
+	return s.size == 0
+}
+
+// Size returns the size of the stack
+func (s *SegmentedStack[T]) Size() int {
+	return s.size
+}
+
+// PushE pushes an element onto the top of the stack
+// If the stack is full and the overflow policy rejects the element, errs.ErrFull is returned
+func (s *SegmentedStack[T]) PushE(e T) error {
+	if !s.push(e) {
+		return errs.ErrFull
+	}
+	return nil
+}
+
+// PopE removes the element at the top of the stack and returns that element
+// If the stack is empty, errs.ErrEmpty is returned
+func (s *SegmentedStack[T]) PopE() (T, error) {
+	t, ok := s.Pop()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// PeekE returns the element at the top of the stack
+// If the stack is empty, errs.ErrEmpty is returned
+func (s *SegmentedStack[T]) PeekE() (T, error) {
+	t, ok := s.Peek()
+	if !ok {
+		return t, errs.ErrEmpty
+	}
+	return t, nil
+}
+
+// Observe registers the hooks called after the stack has been changed
+// and returns a function unregistering them.
+func (s *SegmentedStack[T]) Observe(hooks option.Hooks[T]) (cancel func()) {
+	return s.observers.Observe(hooks)
+}
+
+// segments returns the number of segments allocated, the spare one included
+func (s *SegmentedStack[T]) segments() int {
+	n := 0
+	for seg := s.top; seg != nil; seg = seg.below {
+		n++
+	}
+	if s.spare != nil {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/chenmingyong0423/algorithms/errs"
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentedStack(t *testing.T) {
+	s := NewSegmentedStack[int](4)
+	_, ok := s.Pop()
+	assert.False(t, ok)
+	_, ok = s.Peek()
+	assert.False(t, ok)
+	for i := 0; i < 10; i++ {
+		s.Push(i)
+		v, ok := s.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.Equal(t, 10, s.Size())
+	assert.Equal(t, 3, s.segments())
+	for i := 9; i >= 0; i-- {
+		v, ok := s.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, s.IsEmpty())
+	// only the spare segment is kept
+	assert.Equal(t, 1, s.segments())
+	s.Push(1)
+	assert.Equal(t, 1, s.segments())
+}
+
+func TestSegmentedStack_Boundary(t *testing.T) {
+	s := NewSegmentedStack[*int](2)
+	for i := 0; i < 4; i++ {
+		s.Push(new(int))
+	}
+	assert.Equal(t, 2, s.segments())
+	// pushing and popping across a boundary reuses the spare segment
+	for i := 0; i < 3; i++ {
+		s.Push(new(int))
+		assert.Equal(t, 3, s.segments())
+		s.Pop()
+		assert.Equal(t, 3, s.segments())
+	}
+	s.Pop()
+	s.Pop()
+	assert.Equal(t, 2, s.segments())
+	// the popped slots are zeroed
+	assert.Equal(t, []*int{nil, nil}, s.spare.elements)
+	assert.NotNil(t, s.top.elements[1])
+}
+
+func TestNewSegmentedStack(t *testing.T) {
+	var added, removed []int
+	s := NewSegmentedStack[int](0,
+		option.WithMaxSize[int](2),
+		option.WithHooks[int](option.Hooks[int]{
+			OnAdd: func(index int, e int) {
+				added = append(added, index)
+			},
+			OnRemove: func(index int, e int) {
+				removed = append(removed, index)
+			},
+		}),
+	)
+	assert.Equal(t, defaultSegmentSize, s.segmentSize)
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+	assert.Equal(t, 2, s.Size())
+	s.Pop()
+	assert.Equal(t, []int{0, 1}, added)
+	assert.Equal(t, []int{1}, removed)
+}
+
+func TestSegmentedStack_ErrorVariants(t *testing.T) {
+	s := NewSegmentedStack[int](1, option.WithMaxSize[int](1))
+	_, err := s.PopE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	_, err = s.PeekE()
+	assert.Equal(t, errs.ErrEmpty, err)
+	assert.NoError(t, s.PushE(1))
+	assert.Equal(t, errs.ErrFull, s.PushE(2))
+	v, err := s.PeekE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = s.PopE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestSegmentedStack_Overflow(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      option.OverflowPolicy
+		push        []int
+		wantErr     error
+		wantStack   []int
+		wantEvicted []int
+	}{
+		{
+			name:        "reject",
+			policy:      option.OverflowReject,
+			push:        []int{1, 2, 3, 4, 5, 6},
+			wantErr:     errs.ErrFull,
+			wantStack:   []int{3, 2, 1},
+			wantEvicted: []int{4, 5, 6},
+		},
+		{
+			name:        "evict oldest",
+			policy:      option.OverflowEvictOldest,
+			push:        []int{1, 2, 3, 4, 5, 6},
+			wantStack:   []int{6, 5, 4},
+			wantEvicted: []int{1, 2, 3},
+		},
+		{
+			name:        "evict newest",
+			policy:      option.OverflowEvictNewest,
+			push:        []int{1, 2, 3, 4, 5, 6},
+			wantStack:   []int{6, 2, 1},
+			wantEvicted: []int{3, 4, 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted, removed []int
+			s := NewSegmentedStack[int](2,
+				option.WithMaxSize[int](3),
+				option.WithOverflowPolicy[int](tc.policy),
+				option.WithOnEvict[int](func(elements []int) {
+					evicted = append(evicted, elements...)
+				}),
+				option.WithHooks[int](option.Hooks[int]{
+					OnRemove: func(index int, e int) {
+						removed = append(removed, index)
+					},
+				}),
+			)
+			var err error
+			for _, e := range tc.push {
+				err = s.PushE(e)
+			}
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantEvicted, evicted)
+			if tc.policy == option.OverflowEvictOldest {
+				assert.Equal(t, []int{0, 0, 0}, removed)
+			}
+			var values []int
+			for v, ok := s.Pop(); ok; v, ok = s.Pop() {
+				values = append(values, v)
+			}
+			assert.Equal(t, tc.wantStack, values)
+			assert.LessOrEqual(t, s.segments(), 1)
+			s.Push(7)
+			v, _ := s.Peek()
+			assert.Equal(t, 7, v)
+		})
+	}
+}
+
+func TestSegmentedStack_EvictOldestAcrossSegments(t *testing.T) {
+	s := NewSegmentedStack[*int](2,
+		option.WithMaxSize[*int](4),
+		option.WithOverflowPolicy[*int](option.OverflowEvictOldest),
+	)
+	values := make([]*int, 10)
+	for i := range values {
+		values[i] = new(int)
+		*values[i] = i
+		s.Push(values[i])
+		assert.LessOrEqual(t, s.segments(), 4)
+	}
+	assert.Equal(t, []int{9, 8, 7, 6}, derefAll(s))
+	// the evicted slots are zeroed
+	for seg := s.top; seg != nil; seg = seg.below {
+		if seg.below == nil {
+			for i := 0; i < s.start; i++ {
+				assert.Nil(t, seg.elements[i])
+			}
+		}
+	}
+}
+
+// derefAll returns the elements of the stack from the top, dereferenced
+func derefAll(s *SegmentedStack[*int]) []int {
+	var values []int
+	s.each(func(_ int, e *int) bool {
+		values = append(values, *e)
+		return true
+	})
+	return values
+}
+
+func BenchmarkStack_Push(b *testing.B) {
+	b.Run("ArrayStack", func(b *testing.B) {
+		s := NewArrayStack[int]()
+		for i := 0; i < b.N; i++ {
+			s.Push(i)
+		}
+	})
+	b.Run("SegmentedStack", func(b *testing.B) {
+		s := NewSegmentedStack[int](0)
+		for i := 0; i < b.N; i++ {
+			s.Push(i)
+		}
+	})
+}
@@ -25,3 +25,35 @@ type Stack[T any] interface {
 	IsEmpty() bool
 	Size() int
 }
+
+// ExtendedStack is a Stack with bulk operations, iteration and the stack manipulations of Forth.
+// The depth of an element is its distance from the top, the top element is at depth 0.
+type ExtendedStack[T any] interface {
+	Stack[T]
+	// PushAll pushes the elements in order, the last one ends up on top
+	PushAll(elements ...T)
+	// PopN removes the n elements at the top of the stack, or all of them if there are fewer,
+	// and returns them from the top
+	PopN(n int) []T
+	// PeekN returns the n elements at the top of the stack, or all of them if there are fewer, from the top
+	PeekN(n int) []T
+	// PeekAt returns the element at the depth
+	// If there is no element at the depth, b return false
+	PeekAt(depth int) (T, bool)
+	// Clear removes all the elements of the stack
+	Clear()
+	// Values returns the elements of the stack from the top to the bottom
+	Values() []T
+	// Each calls fn with the elements from the top to the bottom until fn returns false
+	Each(fn func(depth int, e T) bool)
+	// Swap exchanges the two elements at the top of the stack, like SWAP in Forth
+	// If there are fewer than two elements, b return false
+	Swap() bool
+	// Dup pushes a copy of the element at the top of the stack, like DUP in Forth
+	// If the stack is empty or full, b return false
+	Dup() bool
+	// Rotate moves the element at depth n-1 to the top of the stack, like n-1 ROLL in Forth, e.g. Rotate(3) is ROT (2 ROLL).
+	// A negative n moves the top element to depth -n-1 instead, e.g. Rotate(-3) is -ROT.
+	// If there are fewer than |n| elements, b return false
+	Rotate(n int) bool
+}
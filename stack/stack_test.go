@@ -0,0 +1,95 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendedStack(t *testing.T) {
+	newStacks := map[string]func() ExtendedStack[int]{
+		"ArrayStack": func() ExtendedStack[int] {
+			return NewArrayStack[int]()
+		},
+		"LinkedListStack": func() ExtendedStack[int] {
+			return NewLinkedListStack[int]()
+		},
+		"LinkedListStack with a TreeList": func() ExtendedStack[int] {
+			return NewLinkedListStackWithList[int](linkedlist.NewTreeList[int]())
+		},
+	}
+	for name, newStack := range newStacks {
+		t.Run(name, func(t *testing.T) {
+			s := newStack()
+			assert.Empty(t, s.Values())
+			assert.Empty(t, s.PopN(2))
+			assert.False(t, s.Swap())
+			assert.False(t, s.Dup())
+			assert.True(t, s.Rotate(0))
+
+			s.PushAll(1, 2, 3, 4, 5)
+			assert.Equal(t, []int{5, 4, 3, 2, 1}, s.Values())
+			assert.Equal(t, []int{5, 4}, s.PeekN(2))
+			assert.Equal(t, []int{5, 4, 3, 2, 1}, s.PeekN(10))
+			assert.Empty(t, s.PeekN(-1))
+			v, ok := s.PeekAt(1)
+			assert.True(t, ok)
+			assert.Equal(t, 4, v)
+			_, ok = s.PeekAt(5)
+			assert.False(t, ok)
+			_, ok = s.PeekAt(-1)
+			assert.False(t, ok)
+
+			var depths, values []int
+			s.Each(func(depth int, e int) bool {
+				depths = append(depths, depth)
+				values = append(values, e)
+				return depth < 2
+			})
+			assert.Equal(t, []int{0, 1, 2}, depths)
+			assert.Equal(t, []int{5, 4, 3}, values)
+
+			assert.True(t, s.Swap())
+			assert.Equal(t, []int{4, 5, 3, 2, 1}, s.Values())
+			assert.True(t, s.Rotate(3))
+			assert.Equal(t, []int{3, 4, 5, 2, 1}, s.Values())
+			assert.True(t, s.Rotate(-3))
+			assert.Equal(t, []int{4, 5, 3, 2, 1}, s.Values())
+			assert.True(t, s.Rotate(-2))
+			assert.Equal(t, []int{5, 4, 3, 2, 1}, s.Values())
+			assert.True(t, s.Rotate(5))
+			assert.Equal(t, []int{1, 5, 4, 3, 2}, s.Values())
+			assert.True(t, s.Rotate(-5))
+			assert.Equal(t, []int{5, 4, 3, 2, 1}, s.Values())
+			assert.False(t, s.Rotate(6))
+			assert.False(t, s.Rotate(-6))
+
+			assert.True(t, s.Dup())
+			assert.Equal(t, []int{5, 5, 4}, s.PopN(3))
+			assert.Equal(t, []int{3, 2, 1}, s.Values())
+			assert.Equal(t, []int{3, 2, 1}, s.PopN(10))
+			assert.True(t, s.IsEmpty())
+
+			s.PushAll(1, 2)
+			s.Clear()
+			assert.True(t, s.IsEmpty())
+			_, ok = s.Peek()
+			assert.False(t, ok)
+		})
+	}
+}
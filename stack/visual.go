@@ -0,0 +1,67 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/visual"
+)
+
+// Graph returns the slots of the stack from the top to the bottom, see visual.Array.
+func (s *ArrayStack[T]) Graph(opts ...visual.Option[T]) visual.Graph {
+	return visual.Array(s.elements, "top", visual.Apply("ArrayStack", opts...))
+}
+
+// DOT renders the slots of the stack in the Graphviz DOT language, see visual.DOT.
+func (s *ArrayStack[T]) DOT(opts ...visual.Option[T]) string {
+	return visual.DOT(s.Graph(opts...))
+}
+
+// ASCII renders the slots of the stack as a diagram for the terminal, see visual.ASCII.
+func (s *ArrayStack[T]) ASCII(opts ...visual.Option[T]) string {
+	return visual.ASCII(s.Graph(opts...))
+}
+
+// Graph returns the nodes of the list backing the stack, with a top pointer to the last node.
+// The lists other than linkedlist.SinglyLinkedList and linkedlist.DoublyLinkedList are described as an array.
+func (l *LinkedListStack[T]) Graph(opts ...visual.Option[T]) visual.Graph {
+	opts = append([]visual.Option[T]{visual.WithName[T]("LinkedListStack")}, opts...)
+	var g visual.Graph
+	switch list := l.list.(type) {
+	case *linkedlist.SinglyLinkedList[T]:
+		g = list.Graph(opts...)
+	case *linkedlist.DoublyLinkedList[T]:
+		g = list.Graph(opts...)
+	default:
+		return visual.Array(l.list.Values(), "top", visual.Apply("LinkedListStack", opts...))
+	}
+	for _, p := range g.Pointers {
+		if p.Name == "tail" {
+			g.Pointers = append(g.Pointers, visual.Pointer{Name: "top", To: p.To, Broken: p.Broken})
+			break
+		}
+	}
+	return g
+}
+
+// DOT renders the nodes of the stack in the Graphviz DOT language, see visual.DOT.
+func (l *LinkedListStack[T]) DOT(opts ...visual.Option[T]) string {
+	return visual.DOT(l.Graph(opts...))
+}
+
+// ASCII renders the nodes of the stack as a diagram for the terminal, see visual.ASCII.
+func (l *LinkedListStack[T]) ASCII(opts ...visual.Option[T]) string {
+	return visual.ASCII(l.Graph(opts...))
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/visual"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrayStack_ASCII(t *testing.T) {
+	testCases := []struct {
+		name  string
+		stack func() *ArrayStack[int]
+		opts  []visual.Option[int]
+		want  string
+	}{
+		{
+			name: "empty",
+			stack: func() *ArrayStack[int] {
+				return NewArrayStack[int]()
+			},
+			want: "top -> nil\n",
+		},
+		{
+			name: "elements",
+			stack: func() *ArrayStack[int] {
+				s := NewArrayStack[int]()
+				s.PushAll(1, 20, 3)
+				return s
+			},
+			want: "top -> | 3  |\n" +
+				"       | 20 |\n" +
+				"       | 1  |\n" +
+				"       +----+\n",
+		},
+		{
+			name: "max nodes",
+			stack: func() *ArrayStack[int] {
+				s := NewArrayStack[int]()
+				s.PushAll(1, 2, 3)
+				return s
+			},
+			opts: []visual.Option[int]{visual.WithMaxNodes[int](1)},
+			want: "top -> | 3            |\n" +
+				"       | ... (2 more) |\n" +
+				"       +--------------+\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.stack().ASCII(tc.opts...))
+		})
+	}
+}
+
+func TestArrayStack_DOT(t *testing.T) {
+	s := NewArrayStack[string]()
+	s.PushAll("a", "<b>")
+	assert.Equal(t, `digraph "ArrayStack" {
+	rankdir=LR;
+	slots [shape=record, label="<s0> \<b\>|<s1> a"];
+	"ptr_top" [label="top", shape=plaintext];
+	"ptr_top" -> slots:s0;
+}
+`, s.DOT())
+}
+
+func TestLinkedListStack_ASCII(t *testing.T) {
+	testCases := []struct {
+		name  string
+		stack *LinkedListStack[int]
+		want  string
+	}{
+		{
+			name:  "singly",
+			stack: NewLinkedListStack[int](),
+			want: "head   tail,top\n" +
+				"|      |\n" +
+				"v      v\n" +
+				"[1] -> [2] -> nil\n",
+		},
+		{
+			name:  "doubly",
+			stack: NewLinkedListStackWithList[int](linkedlist.NewDoublyLinkedList[int]()),
+			want: "       head    tail,top\n" +
+				"       |       |\n" +
+				"       v       v\n" +
+				"nil <- [1] <-> [2] -> nil\n",
+		},
+		{
+			name:  "tree list",
+			stack: NewLinkedListStackWithList[int](linkedlist.NewTreeList[int]()),
+			want: "top -> | 2 |\n" +
+				"       | 1 |\n" +
+				"       +---+\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.stack.Push(1)
+			tc.stack.Push(2)
+			assert.Equal(t, tc.want, tc.stack.ASCII())
+			assert.Contains(t, tc.stack.DOT(), `digraph "LinkedListStack"`)
+		})
+	}
+}
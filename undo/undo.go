@@ -0,0 +1,284 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package undo provides a command history with undo and redo, transactions and savepoints.
+package undo
+
+import (
+	"errors"
+
+	linkedlist "github.com/chenmingyong0423/algorithms/linked_list"
+	"github.com/chenmingyong0423/algorithms/option"
+	"github.com/chenmingyong0423/algorithms/stack"
+)
+
+var (
+	// ErrNothingToUndo is returned by Undo when there is no command to undo.
+	ErrNothingToUndo = errors.New("undo: nothing to undo")
+	// ErrNothingToRedo is returned by Redo when there is no command to redo.
+	ErrNothingToRedo = errors.New("undo: nothing to redo")
+	// ErrNoTransaction is returned by Commit and Rollback when no transaction is in progress.
+	ErrNoTransaction = errors.New("undo: no transaction in progress")
+	// ErrInTransaction is returned by Undo, Redo and RollbackTo when a transaction is in progress.
+	ErrInTransaction = errors.New("undo: transaction in progress")
+	// ErrInvalidSavepoint is returned by RollbackTo when the savepoint has been discarded,
+	// because the history branched before it or it was dropped from the bounded history.
+	ErrInvalidSavepoint = errors.New("undo: invalid savepoint")
+)
+
+// Command is an operation that can be undone.
+type Command interface {
+	// Do performs the operation, it is also called to redo it.
+	Do() error
+	// Undo reverts the operation.
+	Undo() error
+}
+
+// Func returns a Command calling the functions.
+func Func(do, undo func() error) Command {
+	return funcCommand{do: do, undo: undo}
+}
+
+type funcCommand struct {
+	do   func() error
+	undo func() error
+}
+
+func (c funcCommand) Do() error {
+	return c.do()
+}
+
+func (c funcCommand) Undo() error {
+	return c.undo()
+}
+
+// group is a Command made of the commands of a transaction
+type group []Command
+
+func (g group) Do() error {
+	for i, c := range g {
+		if err := c.Do(); err != nil {
+			// revert the commands already done to keep the group atomic
+			_ = g[:i].Undo()
+			return err
+		}
+	}
+	return nil
+}
+
+func (g group) Undo() error {
+	for i := len(g) - 1; i >= 0; i-- {
+		if err := g[i].Undo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Savepoint marks a position in a History.
+type Savepoint struct {
+	id int
+}
+
+// Option configures a History.
+type Option func(h *History)
+
+// WithMaxDepth keeps at most depth commands in the history, the oldest ones are dropped.
+func WithMaxDepth(depth int) Option {
+	return func(h *History) {
+		h.maxDepth = depth
+	}
+}
+
+// History records the commands done to undo and redo them.
+// It is not safe for concurrent use.
+type History struct {
+	undo     stack.Stack[Command]
+	redo     stack.Stack[Command]
+	maxDepth int
+	// pos is the number of commands done since the history was created, minus the undone ones
+	pos int
+	// floor is the lowest pos reachable by undoing, it grows when commands are dropped
+	floor int
+	// transactions holds the commands of the transactions in progress, the innermost on top
+	transactions stack.Stack[group]
+
+	savepoints  map[int]int
+	savepointID int
+}
+
+// NewHistory returns a new History configured by opts.
+func NewHistory(opts ...Option) *History {
+	h := &History{
+		redo:         stack.NewArrayStack[Command](),
+		transactions: stack.NewArrayStack[group](),
+		savepoints:   make(map[int]int),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	// the undo stack drops its bottom when it is full, which a doubly linked list does in O(1)
+	h.undo = stack.NewLinkedListStackWithList[Command](linkedlist.NewDoublyLinkedListWithOptions[Command](
+		option.WithMaxSize[Command](h.maxDepth),
+		option.WithOverflowPolicy[Command](option.OverflowEvictOldest),
+		option.WithOnEvict[Command](h.drop),
+	))
+	return h
+}
+
+// Do does the command and records it, in the current transaction if there is one.
+// The redo history is discarded. If the command fails, it is not recorded.
+func (h *History) Do(c Command) error {
+	if err := c.Do(); err != nil {
+		return err
+	}
+	if tx, ok := h.transactions.Pop(); ok {
+		h.transactions.Push(append(tx, c))
+		return nil
+	}
+	h.record(c)
+	return nil
+}
+
+// Undo undoes the last command. If it fails, the command stays in the history.
+func (h *History) Undo() error {
+	if !h.transactions.IsEmpty() {
+		return ErrInTransaction
+	}
+	c, ok := h.undo.Peek()
+	if !ok {
+		return ErrNothingToUndo
+	}
+	if err := c.Undo(); err != nil {
+		return err
+	}
+	h.undo.Pop()
+	h.redo.Push(c)
+	h.pos--
+	return nil
+}
+
+// Redo redoes the last undone command. If it fails, the command stays undone.
+func (h *History) Redo() error {
+	if !h.transactions.IsEmpty() {
+		return ErrInTransaction
+	}
+	c, ok := h.redo.Peek()
+	if !ok {
+		return ErrNothingToRedo
+	}
+	if err := c.Do(); err != nil {
+		return err
+	}
+	h.redo.Pop()
+	h.undo.Push(c)
+	h.pos++
+	return nil
+}
+
+// CanUndo checks whether there is a command to undo
+func (h *History) CanUndo() bool {
+	return !h.undo.IsEmpty() && h.transactions.IsEmpty()
+}
+
+// CanRedo checks whether there is a command to redo
+func (h *History) CanRedo() bool {
+	return !h.redo.IsEmpty() && h.transactions.IsEmpty()
+}
+
+// Begin starts a transaction, the commands done until Commit are undone and redone together.
+// Transactions can be nested, a nested transaction is committed into the enclosing one.
+func (h *History) Begin() {
+	h.transactions.Push(nil)
+}
+
+// Commit commits the current transaction.
+func (h *History) Commit() error {
+	tx, ok := h.transactions.Pop()
+	if !ok {
+		return ErrNoTransaction
+	}
+	if len(tx) == 0 {
+		return nil
+	}
+	if outer, ok := h.transactions.Pop(); ok {
+		h.transactions.Push(append(outer, tx))
+		return nil
+	}
+	h.record(tx)
+	return nil
+}
+
+// Rollback undoes the commands of the current transaction and discards them.
+func (h *History) Rollback() error {
+	tx, ok := h.transactions.Pop()
+	if !ok {
+		return ErrNoTransaction
+	}
+	return tx.Undo()
+}
+
+// Savepoint returns a savepoint at the current position of the history.
+func (h *History) Savepoint() Savepoint {
+	h.savepointID++
+	h.savepoints[h.savepointID] = h.pos
+	return Savepoint{id: h.savepointID}
+}
+
+// RollbackTo undoes or redoes the commands until the history is back at the savepoint.
+func (h *History) RollbackTo(sp Savepoint) error {
+	if !h.transactions.IsEmpty() {
+		return ErrInTransaction
+	}
+	pos, ok := h.savepoints[sp.id]
+	if !ok || pos < h.floor {
+		return ErrInvalidSavepoint
+	}
+	for h.pos > pos {
+		if err := h.Undo(); err != nil {
+			return err
+		}
+	}
+	for h.pos < pos {
+		if err := h.Redo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record pushes a command done outside of a transaction onto the history
+func (h *History) record(c Command) {
+	if !h.redo.IsEmpty() {
+		h.redo = stack.NewArrayStack[Command]()
+		// the history branches, the savepoints after the current position cannot be reached anymore
+		for id, pos := range h.savepoints {
+			if pos > h.pos {
+				delete(h.savepoints, id)
+			}
+		}
+	}
+	h.pos++
+	h.undo.Push(c)
+}
+
+// drop is called when the oldest commands are dropped from the bounded history
+func (h *History) drop(commands []Command) {
+	h.floor += len(commands)
+	for id, pos := range h.savepoints {
+		if pos < h.floor {
+			delete(h.savepoints, id)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package undo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// appendCommand appends a value to a slice
+func appendCommand(s *[]int, v int) Command {
+	return Func(func() error {
+		*s = append(*s, v)
+		return nil
+	}, func() error {
+		*s = (*s)[:len(*s)-1]
+		return nil
+	})
+}
+
+func TestHistory_UndoRedo(t *testing.T) {
+	var s []int
+	h := NewHistory()
+	assert.False(t, h.CanUndo())
+	assert.Equal(t, ErrNothingToUndo, h.Undo())
+	assert.Equal(t, ErrNothingToRedo, h.Redo())
+
+	assert.NoError(t, h.Do(appendCommand(&s, 1)))
+	assert.NoError(t, h.Do(appendCommand(&s, 2)))
+	assert.NoError(t, h.Do(appendCommand(&s, 3)))
+	assert.Equal(t, []int{1, 2, 3}, s)
+
+	assert.NoError(t, h.Undo())
+	assert.NoError(t, h.Undo())
+	assert.Equal(t, []int{1}, s)
+	assert.True(t, h.CanRedo())
+
+	assert.NoError(t, h.Redo())
+	assert.Equal(t, []int{1, 2}, s)
+
+	// a new command discards the redo history
+	assert.NoError(t, h.Do(appendCommand(&s, 4)))
+	assert.False(t, h.CanRedo())
+	assert.Equal(t, ErrNothingToRedo, h.Redo())
+	assert.Equal(t, []int{1, 2, 4}, s)
+}
+
+func TestHistory_Errors(t *testing.T) {
+	failure := errors.New("failure")
+	var undoErr error
+	h := NewHistory()
+	assert.Equal(t, failure, h.Do(Func(func() error {
+		return failure
+	}, nil)))
+	assert.False(t, h.CanUndo())
+
+	assert.NoError(t, h.Do(Func(func() error {
+		return nil
+	}, func() error {
+		return undoErr
+	})))
+	undoErr = failure
+	assert.Equal(t, failure, h.Undo())
+	// the command stays in the history
+	assert.True(t, h.CanUndo())
+	undoErr = nil
+	assert.NoError(t, h.Undo())
+	assert.False(t, h.CanUndo())
+}
+
+func TestHistory_MaxDepth(t *testing.T) {
+	var s []int
+	h := NewHistory(WithMaxDepth(2))
+	sp := h.Savepoint()
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, h.Do(appendCommand(&s, i)))
+	}
+	assert.NoError(t, h.Undo())
+	assert.NoError(t, h.Undo())
+	assert.Equal(t, ErrNothingToUndo, h.Undo())
+	assert.Equal(t, []int{1}, s)
+	// the savepoint was dropped with the first command
+	assert.Equal(t, ErrInvalidSavepoint, h.RollbackTo(sp))
+}
+
+func TestHistory_Transaction(t *testing.T) {
+	var s []int
+	h := NewHistory()
+	assert.Equal(t, ErrNoTransaction, h.Commit())
+	assert.Equal(t, ErrNoTransaction, h.Rollback())
+
+	h.Begin()
+	assert.NoError(t, h.Do(appendCommand(&s, 1)))
+	h.Begin()
+	assert.NoError(t, h.Do(appendCommand(&s, 2)))
+	assert.NoError(t, h.Do(appendCommand(&s, 3)))
+	assert.False(t, h.CanUndo())
+	assert.Equal(t, ErrInTransaction, h.Undo())
+	assert.NoError(t, h.Commit())
+	assert.NoError(t, h.Commit())
+	assert.Equal(t, []int{1, 2, 3}, s)
+
+	// the transaction is undone and redone as a whole
+	assert.NoError(t, h.Undo())
+	assert.Empty(t, s)
+	assert.False(t, h.CanUndo())
+	assert.NoError(t, h.Redo())
+	assert.Equal(t, []int{1, 2, 3}, s)
+
+	h.Begin()
+	assert.NoError(t, h.Do(appendCommand(&s, 4)))
+	h.Begin()
+	assert.NoError(t, h.Do(appendCommand(&s, 5)))
+	assert.NoError(t, h.Rollback())
+	assert.Equal(t, []int{1, 2, 3, 4}, s)
+	assert.NoError(t, h.Rollback())
+	assert.Equal(t, []int{1, 2, 3}, s)
+	assert.NoError(t, h.Undo())
+	assert.Empty(t, s)
+
+	// an empty transaction is not recorded
+	h.Begin()
+	assert.NoError(t, h.Commit())
+	assert.False(t, h.CanUndo())
+}
+
+func TestHistory_TransactionRedoFailure(t *testing.T) {
+	failure := errors.New("failure")
+	var s []int
+	var fail bool
+	h := NewHistory()
+	h.Begin()
+	assert.NoError(t, h.Do(appendCommand(&s, 1)))
+	assert.NoError(t, h.Do(Func(func() error {
+		if fail {
+			return failure
+		}
+		return nil
+	}, func() error {
+		return nil
+	})))
+	assert.NoError(t, h.Commit())
+	assert.NoError(t, h.Undo())
+
+	fail = true
+	assert.Equal(t, failure, h.Redo())
+	// the commands of the transaction done before the failure are reverted
+	assert.Empty(t, s)
+	assert.True(t, h.CanRedo())
+}
+
+func TestHistory_Savepoint(t *testing.T) {
+	var s []int
+	h := NewHistory()
+	start := h.Savepoint()
+	assert.NoError(t, h.Do(appendCommand(&s, 1)))
+	assert.NoError(t, h.Do(appendCommand(&s, 2)))
+	middle := h.Savepoint()
+	assert.NoError(t, h.Do(appendCommand(&s, 3)))
+	end := h.Savepoint()
+
+	assert.NoError(t, h.RollbackTo(middle))
+	assert.Equal(t, []int{1, 2}, s)
+	assert.NoError(t, h.RollbackTo(start))
+	assert.Empty(t, s)
+	assert.NoError(t, h.RollbackTo(end))
+	assert.Equal(t, []int{1, 2, 3}, s)
+
+	h.Begin()
+	assert.Equal(t, ErrInTransaction, h.RollbackTo(start))
+	assert.NoError(t, h.Rollback())
+
+	// branching the history discards the savepoints after the branch
+	assert.NoError(t, h.RollbackTo(middle))
+	assert.NoError(t, h.Undo())
+	assert.NoError(t, h.Do(appendCommand(&s, 4)))
+	assert.Equal(t, ErrInvalidSavepoint, h.RollbackTo(middle))
+	assert.Equal(t, ErrInvalidSavepoint, h.RollbackTo(end))
+	assert.NoError(t, h.RollbackTo(start))
+	assert.Empty(t, s)
+}
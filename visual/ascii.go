@@ -0,0 +1,217 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package visual
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ASCII renders the graph as a diagram for the terminal, e.g.
+//
+//	       head            tail
+//	       |               |
+//	       v               v
+//	nil <- [1] <-> [2] <-> [3] -> nil
+//
+// A chain is drawn from the left to the right, an array from the top to the bottom.
+// The broken links are drawn with an x, e.g. "x->" when the prev link of the node on the right is broken,
+// the broken pointers end with a ! and their arrow with an x.
+// The issues follow the diagram, one per line, starting with "! ".
+func ASCII(g Graph) string {
+	var lines []string
+	if g.Kind == KindArray {
+		lines = arrayASCII(g)
+	} else {
+		lines = chainASCII(g)
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteByte('\n')
+	}
+	for _, issue := range g.Issues {
+		b.WriteString("! ")
+		b.WriteString(issue)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// chainASCII returns the pointers on three lines, then the nodes
+func chainASCII(g Graph) []string {
+	// the columns are counted in runes, so that the pointers line up with labels beyond ASCII
+	var body strings.Builder
+	col := 0
+	write := func(s string) {
+		body.WriteString(s)
+		col += utf8.RuneCountInString(s)
+	}
+	cols := make([]int, len(g.Nodes))
+	nilCol, moreCol := -1, -1
+	more := func() {
+		moreCol = col
+		write(fmt.Sprintf("... (%d more)", g.Omitted))
+	}
+	end := func() {
+		nilCol = col
+		write("nil")
+	}
+	if len(g.Nodes) == 0 {
+		if g.Omitted > 0 {
+			more()
+		} else {
+			end()
+		}
+	}
+	for i, n := range g.Nodes {
+		if i == 0 && g.Kind == KindDoubly {
+			switch {
+			case !n.Prev.Broken:
+				write("nil <- ")
+			case n.Prev.To == Nil:
+				write("nil x- ")
+			default:
+				write(fmt.Sprintf("#%d x- ", n.Prev.To))
+			}
+		}
+		cols[i] = col
+		write("[" + n.Label + "]")
+		switch {
+		case n.Next.Broken:
+			write(fmt.Sprintf(" -x #%d", n.Next.To))
+		case n.Next.To == Nil:
+			write(" -> ")
+			end()
+		case n.Next.To >= len(g.Nodes):
+			write(" -> ")
+			more()
+		case g.Kind == KindDoubly:
+			left := "<"
+			if g.Nodes[n.Next.To].Prev.Broken {
+				left = "x"
+			}
+			write(" " + left + "-> ")
+		default:
+			write(" -> ")
+		}
+	}
+
+	// the pointers to the same column are joined
+	type group struct {
+		col    int
+		names  []string
+		broken bool
+	}
+	var groups []*group
+	for _, p := range g.Pointers {
+		at := nilCol
+		switch {
+		case p.To >= len(g.Nodes):
+			at = moreCol
+		case p.To != Nil:
+			at = cols[p.To]
+		}
+		if at < 0 {
+			continue
+		}
+		var gr *group
+		for _, existing := range groups {
+			if existing.col == at {
+				gr = existing
+			}
+		}
+		if gr == nil {
+			gr = &group{col: at}
+			groups = append(groups, gr)
+		}
+		name := p.Name
+		if p.Broken {
+			name += "!"
+			gr.broken = true
+		}
+		gr.names = append(gr.names, name)
+	}
+	if len(groups) == 0 {
+		return []string{body.String()}
+	}
+	var names, stems, tips []byte
+	pad := func(line []byte, col int) []byte {
+		for len(line) < col {
+			line = append(line, ' ')
+		}
+		return line
+	}
+	slices.SortFunc(groups, func(a, b *group) int {
+		return a.col - b.col
+	})
+	for _, gr := range groups {
+		if len(names) > 0 {
+			names = append(names, ' ')
+		}
+		names = append(pad(names, gr.col), strings.Join(gr.names, ",")...)
+		stems = append(pad(stems, gr.col), '|')
+		tip := byte('v')
+		if gr.broken {
+			tip = 'x'
+		}
+		tips = append(pad(tips, gr.col), tip)
+	}
+	return []string{string(names), string(stems), string(tips), body.String()}
+}
+
+// arrayASCII returns the slots from the top to the bottom, then the bottom of the array
+func arrayASCII(g Graph) []string {
+	var top *Pointer
+	if len(g.Pointers) > 0 {
+		top = &g.Pointers[0]
+	}
+	prefix := ""
+	if top != nil {
+		prefix = top.Name + " -> "
+		if top.Broken {
+			prefix = top.Name + " -x "
+		}
+	}
+	if len(g.Nodes) == 0 && g.Omitted == 0 {
+		if top == nil {
+			return []string{"(empty)"}
+		}
+		return []string{prefix + "nil"}
+	}
+	labels := make([]string, 0, len(g.Nodes)+1)
+	for _, n := range g.Nodes {
+		labels = append(labels, n.Label)
+	}
+	if g.Omitted > 0 {
+		labels = append(labels, fmt.Sprintf("... (%d more)", g.Omitted))
+	}
+	width := 0
+	for _, label := range labels {
+		width = max(width, utf8.RuneCountInString(label))
+	}
+	indent := strings.Repeat(" ", len(prefix))
+	lines := make([]string, 0, len(labels)+1)
+	for i, label := range labels {
+		lead := indent
+		if i == 0 && top != nil && top.To == 0 {
+			lead = prefix
+		}
+		lines = append(lines, lead+"| "+label+strings.Repeat(" ", width-utf8.RuneCountInString(label))+" |")
+	}
+	return append(lines, indent+"+"+strings.Repeat("-", width+2)+"+")
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package visual
+
+import (
+	"fmt"
+	"strings"
+)
+
+// brokenStyle highlights the broken links and pointers
+const brokenStyle = `color=red, fontcolor=red, style=dashed`
+
+// DOT renders the graph in the Graphviz DOT language, e.g. for `dot -Tsvg`.
+// The broken links and pointers are drawn red and dashed, and the issues are written as the label of the graph.
+func DOT(g Graph) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(g.Name))
+	b.WriteString("\trankdir=LR;\n")
+	if g.Kind == KindArray {
+		writeArrayDOT(&b, g)
+	} else {
+		writeChainDOT(&b, g)
+	}
+	if len(g.Issues) > 0 {
+		fmt.Fprintf(&b, "\tlabel=%s;\n\tlabelloc=b;\n\tlabeljust=l;\n\tfontcolor=red;\n", quote(strings.Join(g.Issues, "\n")+"\n"))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func writeChainDOT(b *strings.Builder, g Graph) {
+	b.WriteString("\tnode [shape=box];\n")
+	for i, n := range g.Nodes {
+		fmt.Fprintf(b, "\tn%d [label=%s];\n", i, quote(n.Label))
+	}
+	if g.Omitted > 0 {
+		fmt.Fprintf(b, "\tmore [label=%s, shape=plaintext];\n", quote(fmt.Sprintf("... (%d more)", g.Omitted)))
+	}
+	// every link to nil gets its own nil node, a shared one would tangle the layout
+	nils := 0
+	target := func(to int) string {
+		switch {
+		case to == Nil:
+			nils++
+			fmt.Fprintf(b, "\tnil%d [label=\"nil\", shape=plaintext];\n", nils)
+			return fmt.Sprintf("nil%d", nils)
+		case to >= len(g.Nodes):
+			return "more"
+		default:
+			return fmt.Sprintf("n%d", to)
+		}
+	}
+	edge := func(from string, to int, label string, broken bool) {
+		attrs := fmt.Sprintf("label=%s", quote(label))
+		if broken {
+			attrs += ", " + brokenStyle
+		}
+		fmt.Fprintf(b, "\t%s -> %s [%s];\n", from, target(to), attrs)
+	}
+	for i, n := range g.Nodes {
+		edge(fmt.Sprintf("n%d", i), n.Next.To, "next", n.Next.Broken)
+		if g.Kind == KindDoubly {
+			edge(fmt.Sprintf("n%d", i), n.Prev.To, "prev", n.Prev.Broken)
+		}
+	}
+	for _, p := range g.Pointers {
+		fmt.Fprintf(b, "\t%s [label=%s, shape=plaintext];\n", quote("ptr_"+p.Name), quote(p.Name))
+		attrs := ""
+		if p.Broken {
+			attrs = " [" + brokenStyle + "]"
+		}
+		fmt.Fprintf(b, "\t%s -> %s%s;\n", quote("ptr_"+p.Name), target(p.To), attrs)
+	}
+}
+
+func writeArrayDOT(b *strings.Builder, g Graph) {
+	fields := make([]string, 0, len(g.Nodes)+1)
+	for i, n := range g.Nodes {
+		fields = append(fields, fmt.Sprintf("<s%d> %s", i, escapeRecord(escape(n.Label))))
+	}
+	if g.Omitted > 0 {
+		fields = append(fields, escapeRecord(escape(fmt.Sprintf("... (%d more)", g.Omitted))))
+	}
+	if len(fields) == 0 {
+		fields = append(fields, "empty")
+	}
+	// with rankdir=LR the fields of a record are stacked vertically
+	fmt.Fprintf(b, "\tslots [shape=record, label=\"%s\"];\n", strings.Join(fields, "|"))
+	for _, p := range g.Pointers {
+		fmt.Fprintf(b, "\t%s [label=%s, shape=plaintext];\n", quote("ptr_"+p.Name), quote(p.Name))
+		if p.To == Nil {
+			fmt.Fprintf(b, "\tnil [label=\"nil\", shape=plaintext];\n\t%s -> nil;\n", quote("ptr_"+p.Name))
+		} else {
+			fmt.Fprintf(b, "\t%s -> slots:s%d;\n", quote("ptr_"+p.Name), p.To)
+		}
+	}
+}
+
+// quote returns s as a DOT string
+func quote(s string) string {
+	return `"` + escape(s) + `"`
+}
+
+// escape escapes s for a DOT string, the lines are left justified
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\l`)
+	return r.Replace(s)
+}
+
+// escapeRecord escapes the characters with a meaning in the label of a record node, s must already be escaped
+func escapeRecord(s string) string {
+	r := strings.NewReplacer(`|`, `\|`, `{`, `\{`, `}`, `\}`, `<`, `\<`, `>`, `\>`)
+	return r.Replace(s)
+}
@@ -0,0 +1,145 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package visual renders the node structure of the lists and the stacks of the library
+// to Graphviz DOT and to ASCII diagrams, for teaching and debugging.
+package visual
+
+import "fmt"
+
+// Nil is the target of a link to no node.
+const Nil = -1
+
+// Kind is the shape of a Graph.
+type Kind int
+
+const (
+	// KindSingly is a chain of nodes linked by next.
+	KindSingly Kind = iota
+	// KindDoubly is a chain of nodes linked by next and prev.
+	KindDoubly
+	// KindArray is a contiguous array of slots, the nodes are from the top to the bottom.
+	KindArray
+)
+
+// Graph describes the nodes of a collection, as found while walking them.
+type Graph struct {
+	Name string
+	Kind Kind
+	// Nodes are in the order of the walk, a link refers to a node by its index.
+	// A link to an index past the nodes refers to the omitted nodes.
+	Nodes []Node
+	// Omitted is the number of nodes left out because of the max nodes option.
+	Omitted int
+	// Pointers are the named references to the nodes, e.g. head and tail.
+	Pointers []Pointer
+	// Issues describe the inconsistencies found while walking the nodes.
+	Issues []string
+}
+
+// Node is a node of a Graph.
+type Node struct {
+	Label string
+	Next  Link
+	// Prev is only used by KindDoubly.
+	Prev Link
+}
+
+// Link is a reference from a node to another one, or to Nil.
+type Link struct {
+	To int
+	// Broken tells whether the link does not point where it should.
+	Broken bool
+}
+
+// Pointer is a named reference to a node, or to Nil.
+type Pointer struct {
+	Name   string
+	To     int
+	Broken bool
+}
+
+// Broken checks whether some link or pointer of the graph is broken
+func (g Graph) Broken() bool {
+	for _, n := range g.Nodes {
+		if n.Next.Broken || n.Prev.Broken {
+			return true
+		}
+	}
+	for _, p := range g.Pointers {
+		if p.Broken {
+			return true
+		}
+	}
+	return false
+}
+
+// Options configures how the nodes of a collection are described.
+type Options[T any] struct {
+	// Name is the name of the graph, e.g. the name of the DOT digraph.
+	Name string
+	// Format returns the label of an element.
+	Format func(e T) string
+	// MaxNodes is the maximum number of nodes described, zero means unlimited.
+	MaxNodes int
+	// Check tells whether the links are checked and the broken ones highlighted.
+	Check bool
+}
+
+// Option configures the Options.
+type Option[T any] func(o *Options[T])
+
+// Apply returns the Options configured by opts.
+// By default the elements are formatted with fmt.Sprint and the links are checked.
+func Apply[T any](name string, opts ...Option[T]) Options[T] {
+	o := Options[T]{
+		Name: name,
+		Format: func(e T) string {
+			return fmt.Sprint(e)
+		},
+		Check: true,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithName sets the name of the graph.
+func WithName[T any](name string) Option[T] {
+	return func(o *Options[T]) {
+		o.Name = name
+	}
+}
+
+// WithFormat sets the function returning the label of an element.
+func WithFormat[T any](format func(e T) string) Option[T] {
+	return func(o *Options[T]) {
+		o.Format = format
+	}
+}
+
+// WithMaxNodes limits the description to the first n nodes, the others are summarized.
+func WithMaxNodes[T any](n int) Option[T] {
+	return func(o *Options[T]) {
+		o.MaxNodes = n
+	}
+}
+
+// WithCheck enables or disables the checks of the links, enabled by default.
+func WithCheck[T any](check bool) Option[T] {
+	return func(o *Options[T]) {
+		o.Check = check
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package visual
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDOT(t *testing.T) {
+	testCases := []struct {
+		name  string
+		graph Graph
+		want  string
+	}{
+		{
+			name: "omitted and escaped",
+			graph: Graph{
+				Name: `a "list"`,
+				Kind: KindSingly,
+				Nodes: []Node{
+					{Label: `x\y`, Next: Link{To: 1}},
+				},
+				Omitted:  2,
+				Pointers: []Pointer{{Name: "head", To: 0}, {Name: "tail", To: 1}},
+			},
+			want: `digraph "a \"list\"" {
+	rankdir=LR;
+	node [shape=box];
+	n0 [label="x\\y"];
+	more [label="... (2 more)", shape=plaintext];
+	n0 -> more [label="next"];
+	"ptr_head" [label="head", shape=plaintext];
+	"ptr_head" -> n0;
+	"ptr_tail" [label="tail", shape=plaintext];
+	"ptr_tail" -> more;
+}
+`,
+		},
+		{
+			name: "empty array",
+			graph: Graph{
+				Name:     "stack",
+				Kind:     KindArray,
+				Pointers: []Pointer{{Name: "top", To: Nil}},
+			},
+			want: `digraph "stack" {
+	rankdir=LR;
+	slots [shape=record, label="empty"];
+	"ptr_top" [label="top", shape=plaintext];
+	nil [label="nil", shape=plaintext];
+	"ptr_top" -> nil;
+}
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, DOT(tc.graph))
+		})
+	}
+}
+
+func TestASCII(t *testing.T) {
+	testCases := []struct {
+		name  string
+		graph Graph
+		want  string
+	}{
+		{
+			name: "labels beyond ascii",
+			graph: Graph{
+				Kind: KindSingly,
+				Nodes: []Node{
+					{Label: "é", Next: Link{To: 1}},
+					{Label: "z", Next: Link{To: Nil}},
+				},
+				Pointers: []Pointer{{Name: "head", To: 0}, {Name: "tail", To: 1}},
+			},
+			want: "head   tail\n" +
+				"|      |\n" +
+				"v      v\n" +
+				"[é] -> [z] -> nil\n",
+		},
+		{
+			name: "without pointers",
+			graph: Graph{
+				Kind:    KindDoubly,
+				Omitted: 3,
+			},
+			want: "... (3 more)\n",
+		},
+		{
+			name: "array without pointer",
+			graph: Graph{
+				Kind: KindArray,
+			},
+			want: "(empty)\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ASCII(tc.graph))
+		})
+	}
+}
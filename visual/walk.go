@@ -0,0 +1,165 @@
+// Copyright 2023 chenmingyong0423
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package visual
+
+import "fmt"
+
+// Chain describes a chain of nodes of type P, a pointer type, through its accessors.
+// Prev is only used by KindDoubly.
+type Chain[P comparable, T any] struct {
+	Head  P
+	Tail  P
+	Size  int
+	Next  func(n P) P
+	Prev  func(n P) P
+	Value func(n P) T
+}
+
+// Walk walks the chain from its head and returns its Graph of the kind, KindSingly or KindDoubly.
+// When the checks are enabled, the walk detects the cycles, the prev links not pointing to the previous node,
+// the tail not pointing to the last node and the size not matching the number of nodes.
+// Otherwise the walk trusts the size and the links are assumed to be consistent.
+func (c Chain[P, T]) Walk(kind Kind, o Options[T]) Graph {
+	var zero P
+	g := Graph{Name: o.Name, Kind: kind}
+
+	// nodes are all the nodes reachable from the head, index is only used by the checks
+	var nodes []P
+	index := make(map[P]int)
+	cycle := Nil
+	for n := c.Head; n != zero; n = c.Next(n) {
+		if !o.Check && len(nodes) == c.Size {
+			break
+		}
+		if o.Check {
+			if i, ok := index[n]; ok {
+				cycle = i
+				break
+			}
+			index[n] = len(nodes)
+		}
+		nodes = append(nodes, n)
+	}
+
+	shown := len(nodes)
+	if o.MaxNodes > 0 && shown > o.MaxNodes {
+		shown = o.MaxNodes
+	}
+	g.Omitted = len(nodes) - shown
+	g.Nodes = make([]Node, shown)
+	label := func(i int) string {
+		return fmt.Sprintf("#%d (%s)", i, o.Format(c.Value(nodes[i])))
+	}
+	for i := range g.Nodes {
+		g.Nodes[i].Label = o.Format(c.Value(nodes[i]))
+		g.Nodes[i].Next = Link{To: i + 1}
+		if i == len(nodes)-1 {
+			g.Nodes[i].Next = Link{To: Nil}
+			if cycle != Nil {
+				g.Nodes[i].Next = Link{To: cycle, Broken: true}
+				g.Issues = append(g.Issues, fmt.Sprintf("next of %s points back to %s, the list has a cycle", label(i), label(cycle)))
+			}
+		}
+		if kind == KindDoubly {
+			g.Nodes[i].Prev = Link{To: i - 1}
+		}
+	}
+
+	if kind == KindDoubly && o.Check {
+		for i, n := range nodes {
+			var want P
+			if i > 0 {
+				want = nodes[i-1]
+			}
+			got := c.Prev(n)
+			if got == want {
+				continue
+			}
+			link := Link{To: Nil, Broken: true}
+			desc := "nil"
+			if j, ok := index[got]; ok {
+				link.To, desc = j, label(j)
+			} else if got != zero {
+				desc = "a node outside the list"
+			}
+			if i < len(g.Nodes) {
+				g.Nodes[i].Prev = link
+			}
+			wantDesc := "nil"
+			if i > 0 {
+				wantDesc = label(i - 1)
+			}
+			g.Issues = append(g.Issues, fmt.Sprintf("prev of %s is %s, want %s", label(i), desc, wantDesc))
+		}
+	}
+
+	head := Pointer{Name: "head", To: Nil}
+	if len(nodes) > 0 {
+		head.To = 0
+	}
+	tail := Pointer{Name: "tail", To: len(nodes) - 1}
+	if o.Check {
+		want := zero
+		if len(nodes) > 0 {
+			want = nodes[len(nodes)-1]
+		}
+		if i, ok := index[c.Tail]; ok {
+			tail.To = i
+		} else {
+			tail.To = Nil
+		}
+		// a list with a cycle has no last node to compare the tail with
+		if cycle == Nil && c.Tail != want {
+			tail.Broken = true
+			desc := "nil"
+			if tail.To != Nil {
+				desc = label(tail.To)
+			} else if c.Tail != zero {
+				desc = "a node outside the list"
+			}
+			wantDesc := "nil"
+			if want != zero {
+				wantDesc = label(len(nodes) - 1)
+			}
+			g.Issues = append(g.Issues, fmt.Sprintf("tail points to %s, want %s", desc, wantDesc))
+		}
+		if len(nodes) != c.Size {
+			g.Issues = append(g.Issues, fmt.Sprintf("size is %d but %d nodes are reachable from head", c.Size, len(nodes)))
+		}
+	}
+	g.Pointers = append(g.Pointers, head, tail)
+	return g
+}
+
+// Array returns the Graph of KindArray of the elements, from the bottom to the top,
+// with a pointer of the name to the top.
+func Array[T any](elements []T, pointer string, o Options[T]) Graph {
+	g := Graph{Name: o.Name, Kind: KindArray}
+	shown := len(elements)
+	if o.MaxNodes > 0 && shown > o.MaxNodes {
+		shown = o.MaxNodes
+	}
+	g.Omitted = len(elements) - shown
+	g.Nodes = make([]Node, shown)
+	for i := range g.Nodes {
+		g.Nodes[i] = Node{Label: o.Format(elements[len(elements)-1-i]), Next: Link{To: Nil}}
+	}
+	top := Pointer{Name: pointer, To: Nil}
+	if len(elements) > 0 {
+		top.To = 0
+	}
+	g.Pointers = append(g.Pointers, top)
+	return g
+}